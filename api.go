@@ -4,31 +4,163 @@
 package chronos
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"sort"
 	"sync"
 	"time"
 )
 
+// Error returned when modifying or scheduling an already scheduled job
+var ErrStarted = errors.New("job already scheduled")
+
+// Error returned when rescheduling a job that has already ended
+var ErrEnded = errors.New("job already ended")
+
 type Job struct {
-	task   func() // Task to be scheduled
+	task   func(context.Context) // Task to be scheduled
 	times, // Times that it can be executed, -1 means no limit
-	n int // Times that it has been executed
-	aux      auxiliar   // Holds the values for following API calls
+	n, // Times that it has been executed
+	skipped int // Times that a scheduled execution was skipped
+	aux      auxiliar  // Holds the values for following API calls
 	schedule scheduler // Scheduler to determine when to run the job
 	quit,    // Channel for quitting the scheduled job
-	skip chan struct{} // Channel for executing the task inmediately
-	mutex sync.Mutex // Mutex to avoid concurrent executions of the same task
+	skip, // Channel for executing the task inmediately
+	reset, // Channel for rearming the timer after the scheduler changed
+	done, // Channel closed when the scheduling goroutine exits
+	exhausted chan struct{} // Channel closed when the last allowed execution starts
+	ctx       context.Context    // Context passed to the task
+	cancel    context.CancelFunc // Cancels ctx when a shutdown deadline expires
+	mutex     sync.Mutex         // Mutex to avoid concurrent executions of the same task
+	lock      sync.Mutex         // Mutex to protect the execution counters and state
+	started   bool               // Wether Start() has already scheduled the job
+	rearm     bool               // Wether nextRun was computed by Reschedule
+	nextRun   time.Time          // Next scheduled execution, zero if there is none
+	errs      []error            // Misuses of the builder, reported by Start()
+	running   sync.WaitGroup     // In-flight executions of the task
+	logger    Logger             // Receives scheduling events, nil disables them
+	beforeRun func() bool        // Decides wether each execution happens
+	noDrift   bool               // Drift-corrected mode
+	wheel     *Wheel             // Wheel scheduling the job, nil for its own goroutine
+	name      string             // Name identifying the job in its state store
+	store     StateStore         // Keeps the state across restarts, nil if not needed
+	lastRun   time.Time          // When the last execution started
+	index     int                // Index of the event after the last fired one
+	blocking  bool               // Executed on the goroutine that called Run()
+	lastEnd   time.Time          // When the last execution ended
+	rand      *rand.Rand         // Source of randomness, nil for the package one
+	completed func()             // Runs once the job and its executions end
+	finishing sync.Once          // Releases the job only once
+	failed    bool               // Wether the last execution reported an error
+	parent    *Job               // Job whose executions trigger this one, if any
+	onFailure bool               // Wether failed executions of the parent trigger it
+	followers []*Job             // Jobs triggered by the executions of this one
+	minGap    time.Duration      // Minimum interval between execution starts
+	gapped    bool               // Wether an execution is waiting for MinGap
+	watchdog  time.Duration      // Interval of the watchdog checks, 0 disables it
+	fired     time.Time          // Event of the last dispatched execution
+	onClose   func()             // Runs when the daily window closes
+	ticks     chan time.Time     // Receives the events instead of running a task
+	tickCap   int                // Size of the ticks buffer
+	dropped,  // Ticks dropped because the buffer was full
+	maxBurst, // Executions in a row allowed to run late, 0 means no limit
+	burst, // Executions in a row that waited for the previous one
+	gen, // Generation of the scheduling goroutine, see watch()
+	restarts int // Times that the watchdog restarted the scheduling goroutine
 }
 
 // Job construction with task assignment
 
 func Schedule(f func()) *Job {
+	return ScheduleContext(func(context.Context) { f() })
+}
+
+// Context-aware tasks receive a context that is cancelled if a Shutdown
+// deadline expires while they are still running
+func ScheduleContext(f func(context.Context)) *Job {
+	ctx, cancel := context.WithCancel(context.Background())
 	return &Job{task: f, times: -1, quit: make(chan struct{}, 1),
-		skip: make(chan struct{}, 1)}
+		skip: make(chan struct{}, 1), reset: make(chan struct{}, 1),
+		done: make(chan struct{}), exhausted: make(chan struct{}),
+		ctx: ctx, cancel: cancel}
+}
+
+// Job construction with a single execution at the given time, or as soon as
+// possible if it is already past unless SkipIfPast is defined
+
+func ScheduleAt(t time.Time, f func()) *Job {
+	j := Schedule(f).Once().period(onceKind, 0, 0)
+	j.aux.start = t
+	return j
+}
+
+// Job construction with a single execution after the given time
+
+func ScheduleAfter(d time.Duration, f func()) *Job {
+	return ScheduleAt(now().Add(d), f)
+}
+
+// Job construction with a task that receives an argument, allowing the same
+// function to be scheduled with different arguments
+func ScheduleArg[T any](arg T, f func(T)) *Job {
+	return ScheduleContext(func(context.Context) { f(arg) })
+}
+
+// Clone returns a new unscheduled job with the same task and configuration,
+// which can be modified without affecting the original one, but none of its
+// executions or scheduling state. Clones of a job with a state store are not
+// named, as they would share its state, so they must be given their own name.
+func (j *Job) Clone() *Job {
+	j.lock.Lock()
+	defer j.lock.Unlock()
+
+	c := ScheduleContext(j.task)
+	c.times = j.times
+	c.aux = j.aux.clone()
+	c.logger = j.logger
+	c.beforeRun = j.beforeRun
+	c.noDrift = j.noDrift
+	c.wheel = j.wheel
+	c.store = j.store
+	if j.store == nil {
+		c.name = j.name
+	}
+	c.minGap = j.minGap
+	c.maxBurst = j.maxBurst
+	c.rand = j.rand
+	c.completed = j.completed
+	c.onFailure = j.onFailure
+	c.watchdog = j.watchdog
+	c.tickCap = j.tickCap
+	c.onClose = j.onClose
+	// Tick jobs get their own channel
+	if j.ticks != nil {
+		c.task = nil
+	}
+	if j.parent != nil {
+		c.After(j.parent)
+	}
+	// Clones take their own jitter
+	for _, a := range append([]*auxiliar{&c.aux}, alternatives(&c.aux)...) {
+		a.seeded = false
+	}
+	for _, err := range j.errs {
+		if err != ErrStarted {
+			c.errs = append(c.errs, err)
+		}
+	}
+	return c
 }
 
-// Defining the number of times
+// Defining the number of times, only actual executions of the task are
+// counted. Jobs of ScheduleAt always run once.
 
 func (j *Job) NTimes(n int) *Job {
+	if !j.mutable() {
+		return j
+	}
 	j.times = n
 	return j
 }
@@ -44,13 +176,20 @@ func (j *Job) Twice() *Job {
 // Defining the period size in units
 
 func (j *Job) Every(times ...int) *Job {
+	if !j.mutable() {
+		return j
+	}
 	switch len(times) {
 	case 0:
 		j.aux.ammount = 1
 	case 1:
+		if times[0] < 0 {
+			return j.fail(fmt.Errorf("%d is not a valid period", times[0]))
+		}
 		j.aux.ammount = times[0]
 	default:
-		panic("Too many arguments in Job.Every()")
+		return j.fail(fmt.Errorf("too many arguments in Job.Every(): %d",
+			len(times)))
 	}
 	return j
 }
@@ -58,8 +197,25 @@ func (j *Job) Every(times ...int) *Job {
 // Defining the period's unit duration
 
 func (j *Job) duration(d time.Duration) *Job {
-	j.aux.kind = periodicKind
-	j.aux.unit = d
+	return j.period(periodicKind, d, 0)
+}
+
+// Selects the kind of scheduler and its unit, only one can be selected although
+// it can be selected again
+func (j *Job) period(kind int, unit time.Duration, months int) *Job {
+	if !j.mutable() {
+		return j
+	}
+	if j.aux.selected && (j.aux.kind != kind || j.aux.unit != unit ||
+		j.aux.months != months) {
+		return j.fail(fmt.Errorf("conflicting period units: %s and %s",
+			unitName(j.aux.kind, j.aux.unit, j.aux.months),
+			unitName(kind, unit, months)))
+	}
+	j.aux.kind = kind
+	j.aux.unit = unit
+	j.aux.months = months
+	j.aux.selected = true
 	return j
 }
 
@@ -128,102 +284,1189 @@ func (j *Job) Weeks() *Job {
 }
 
 func (j *Job) Month() *Job {
-	j.aux.kind = monthlyKind
-	return j
+	return j.period(monthlyKind, 0, 1)
 }
 
 func (j *Job) Months() *Job {
 	return j.Month()
 }
 
+func (j *Job) Quarter() *Job {
+	return j.period(monthlyKind, 0, 3)
+}
+
+func (j *Job) Quarters() *Job {
+	return j.Quarter()
+}
+
+func (j *Job) Semester() *Job {
+	return j.period(monthlyKind, 0, 6)
+}
+
+func (j *Job) Semesters() *Job {
+	return j.Semester()
+}
+
 func (j *Job) Year() *Job {
-	j.aux.kind = yearlyKind
-	return j
+	return j.period(yearlyKind, 0, 0)
 }
 
 func (j *Job) Years() *Job {
 	return j.Year()
 }
 
-// Defining if it should run at the start of the cycle
+// Defining a quarterly period on the given day of the given month of each
+// quarter, 0 being its first month, so EveryQuarterOn(1, 15) runs on the 15th
+// of February, May, August and November, unless another amount of quarters was
+// given. Days past the end of a month are clamped to its last day.
+
+func (j *Job) EveryQuarterOn(month, day int) *Job {
+	if month < 0 || month > 2 {
+		j.fail(fmt.Errorf("%d is not a valid month of a quarter", month))
+	}
+	if day < 1 || day > 31 {
+		j.fail(fmt.Errorf("%d is not a valid day of a month", day))
+	}
+	if !j.defaultEvery().Quarter().mutable() {
+		return j
+	}
+	j.aux.quarterMonth = month
+	j.aux.quarterDay = day
+	return j
+}
+
+// Defining a daily cadence whose time is chosen for each day by a function,
+// which receives the midnight of the day in the location of the starting time
+// and returns the instant of that day's execution, or a zero time to skip the
+// day, e.g. to run every day at sunset
+
+func (j *Job) EveryDayAt(at func(date time.Time) time.Time) *Job {
+	if !j.period(dynamicKind, 0, 0).mutable() {
+		return j
+	}
+	j.aux.dailyAt = at
+	return j
+}
+
+// Defining the times of the day, as "HH:MM" in the location of the starting
+// time, when the task runs every day or, if defined, every given amount of days
+
+func (j *Job) AtTimes(times ...string) *Job {
+	if !j.mutable() {
+		return j
+	}
+	if len(times) == 0 {
+		return j.fail(errors.New("missing times of the day"))
+	}
+	minutes := make([]int, 0, len(times))
+	for _, at := range times {
+		t, err := time.Parse("15:04", at)
+		if err != nil {
+			j.fail(fmt.Errorf("%q is not a valid time of the day", at))
+			continue
+		}
+		minutes = append(minutes, 60*t.Hour()+t.Minute())
+	}
+	// Sorted and without duplicates
+	sort.Ints(minutes)
+	j.aux.atTimes = nil
+	for _, m := range minutes {
+		if n := len(j.aux.atTimes); n == 0 || j.aux.atTimes[n-1] != m {
+			j.aux.atTimes = append(j.aux.atTimes, m)
+		}
+	}
+	return j
+}
+
+// Defining an offset added to the times chosen by EveryDayAt, e.g. -30 minutes
+// to run half an hour before sunset
+
+func (j *Job) Offset(d time.Duration) *Job {
+	if !j.mutable() {
+		return j
+	}
+	j.aux.offset = d
+	return j
+}
+
+// Adding the cadence of another job, the task runs whenever any of them is due
+// and only the cadences due at that instant advance. Only the period, starting
+// and ending times of the other job are used, its task and count are ignored
+
+func (j *Job) Or(other *Job) *Job {
+	if !j.mutable() {
+		return j
+	}
+	if err := other.Err(); err != nil {
+		j.fail(err)
+	}
+	// A copy, as seeding the jitter writes into the cadences
+	other.lock.Lock()
+	aux := other.aux.clone()
+	other.lock.Unlock()
+	j.aux.alternatives = append(j.aux.alternatives, aux)
+	return j
+}
+
+// Defining if it should run at the start of the cycle, when called the first
+// execution happens at the first period boundary after the starting time, so
+// Once().NotInmediately() runs the task exactly once, one period after start
 
 func (j *Job) NotInmediately() *Job {
+	if !j.mutable() {
+		return j
+	}
 	j.aux.notInmediately = true
 	return j
 }
 
+// Defining the alignment of the starting time to the boundary of the period in
+// its location, e.g. Every(6).Hours().Aligned() runs at 00:00, 06:00, 12:00
+// and 18:00 no matter when the job is scheduled. Weeks start on the given day,
+// Monday by default. With NotInmediately, the first execution happens at the
+// first boundary after the starting time.
+
+func (j *Job) Aligned(weekStart ...time.Weekday) *Job {
+	if !j.mutable() {
+		return j
+	}
+	switch len(weekStart) {
+	case 0:
+		if !j.aux.weekStartSet {
+			j.aux.weekStart = time.Monday
+		}
+	case 1:
+		j.aux.weekStart = weekStart[0]
+		j.aux.weekStartSet = true
+	default:
+		return j.fail(fmt.Errorf("too many arguments in Job.Aligned(): %d",
+			len(weekStart)))
+	}
+	j.aux.aligned = true
+	return j
+}
+
+// Defining the first day of the week. Periods of several weeks run on the
+// weeks whose number, counting the weeks that start on this day since the Unix
+// epoch, is a multiple of the period, so Every(2).Weeks() keeps its phase
+// across restarts; the weekday and time of the day are those of the starting
+// time. Aligned periods in weeks start on this day.
+
+func (j *Job) WeeksStartingOn(d time.Weekday) *Job {
+	if !j.mutable() {
+		return j
+	}
+	j.aux.weekStart = d
+	j.aux.weekStartSet = true
+	return j
+}
+
+// Defining that periods in weeks select the weeks by their ISO week number, so
+// Every(2).Weeks().ByISOWeek() runs on odd weeks, which is kept across restarts
+// without depending on the starting time. The first selected week number can
+// be given, 1 by default, e.g. ByISOWeek(2) runs on even weeks. The weekday and
+// time of the day of the executions are taken from the starting time. After a
+// year with 53 weeks, its last week and the first one of the next year may be
+// both selected.
+
+func (j *Job) ByISOWeek(first ...int) *Job {
+	if !j.mutable() {
+		return j
+	}
+	switch len(first) {
+	case 0:
+		j.aux.firstISOWeek = 1
+	case 1:
+		j.aux.firstISOWeek = first[0]
+	default:
+		return j.fail(fmt.Errorf("too many arguments in Job.ByISOWeek(): %d",
+			len(first)))
+	}
+	j.aux.byISOWeek = true
+	return j
+}
+
+// Every hour, on the hour, unless another amount of hours was given
+
+func (j *Job) OnTheHour() *Job {
+	return j.defaultEvery().Hour().Aligned()
+}
+
+// Every day at midnight, unless another amount of days was given
+
+func (j *Job) AtMidnight() *Job {
+	return j.defaultEvery().Day().Aligned()
+}
+
+// Every quarter, unless another amount of quarters was given
+
+func (j *Job) Quarterly() *Job {
+	return j.defaultEvery().Quarter()
+}
+
+// Every semester, unless another amount of semesters was given
+
+func (j *Job) Biannually() *Job {
+	return j.defaultEvery().Semester()
+}
+
+// Sets the period size to 1 unit if it was not defined
+func (j *Job) defaultEvery() *Job {
+	if j.aux.ammount == 0 {
+		return j.Every()
+	}
+	return j
+}
+
+// Defining wether the single execution of ScheduleAt happens as soon as
+// possible, the default, or is skipped when its time is already past
+
+func (j *Job) RunIfPast() *Job {
+	if j.mutable() {
+		j.aux.skipPast = false
+		j.aux.pastSet = true
+	}
+	return j
+}
+
+func (j *Job) SkipIfPast() *Job {
+	if j.mutable() {
+		j.aux.skipPast = true
+		j.aux.pastSet = true
+	}
+	return j
+}
+
+// Defining a hook that runs once the job has ended, either stopped or with
+// nothing else to schedule, and its last execution has finished
+
+func (j *Job) OnComplete(f func()) *Job {
+	if !j.mutable() {
+		return j
+	}
+	j.completed = f
+	return j
+}
+
+// Defining a hook that runs right before each execution, while holding the
+// same lock as the task, and can veto it by returning false. Vetoed executions
+// count as skipped, not as executed, e.g. to let only one node of a cluster
+// run a shared job.
+
+func (j *Job) BeforeRun(f func() bool) *Job {
+	if !j.mutable() {
+		return j
+	}
+	j.beforeRun = f
+	return j
+}
+
+// Defining drift-corrected mode, each event is kept on the grid anchored at the
+// starting time (start + k*period) with millisecond precision according to the
+// wall clock, no matter how long the job has been running
+
+func (j *Job) NoDrift() *Job {
+	if !j.mutable() {
+		return j
+	}
+	j.noDrift = true
+	return j
+}
+
+// Defining the minimum interval between the starts of consecutive executions.
+// Executions due earlier, e.g. those that waited for a slow one, are delayed
+// until then.
+
+func (j *Job) MinGap(d time.Duration) *Job {
+	if d < 0 {
+		return j.fail(fmt.Errorf("%v is not a valid gap between executions", d))
+	}
+	if j.mutable() {
+		j.minGap = d
+	}
+	return j
+}
+
+// Defining how many executions in a row may run late because they waited for
+// the previous one to finish, e.g. after it took several periods. The rest of
+// them count as skipped, resuming the normal cadence afterwards.
+
+func (j *Job) MaxBurst(n int) *Job {
+	if n < 1 {
+		return j.fail(fmt.Errorf("%d is not a valid burst size", n))
+	}
+	if j.mutable() {
+		j.maxBurst = n
+	}
+	return j
+}
+
 // Defining the starting and ending times
 
 func (j *Job) At(t time.Time) *Job {
+	if !j.mutable() {
+		return j
+	}
 	j.aux.start = t
 	return j
 }
 
 func (j *Job) In(d time.Duration) *Job {
-	return j.At(time.Now().Add(d))
+	return j.At(now().Add(d))
+}
+
+// Starting at the next time that the clock shows the time of the day of t, in
+// its location, which is today unless it has already passed
+
+func (j *Job) AtClock(t time.Time) *Job {
+	loc := t.Location()
+	now := now().In(loc)
+	year, month, day := now.Date()
+	start := wallDate(year, month, day, t.Hour(), t.Minute(), t.Second(),
+		t.Nanosecond(), loc)
+	if start.Before(now) {
+		start = wallDate(year, month, day+1, t.Hour(), t.Minute(), t.Second(),
+			t.Nanosecond(), loc)
+	}
+	return j.At(start)
+}
+
+// Like AtClock, with a local time of the day given as "15:04" or "15:04:05"
+
+func (j *Job) AtClockString(clock string) *Job {
+	for _, layout := range []string{"15:04", "15:04:05"} {
+		if t, err := time.ParseInLocation(layout, clock, time.Local); err == nil {
+			return j.AtClock(t)
+		}
+	}
+	return j.fail(fmt.Errorf("%q is not a valid time of the day", clock))
 }
 
 func (j *Job) Until(t time.Time) *Job {
+	if !j.mutable() {
+		return j
+	}
 	j.aux.end = t
 	return j
 }
 
 // Scheduling the task
 
+// Start validates everything recorded by the builder, returning an error that
+// lists all the problems found, and starts the job if there were none. Once
+// started, the job can not be modified nor started again.
+func (j *Job) Start() error {
+	if err := j.prepare(false); err != nil {
+		return err
+	}
+	// Dependent jobs are executed by their parent, and end with it
+	if j.parent != nil {
+		select {
+		case <-j.parent.done:
+			j.finish()
+		default:
+		}
+		return nil
+	}
+	switch {
+	case j.wheel != nil:
+		j.wheel.wake(j)
+	case j.watchdog > 0:
+		go j.watch()
+	default:
+		go j.loop()
+	}
+	return nil
+}
+
+// Done starts the job as Start() does, also returning the channels that
+// execute the task inmediately and stop the job when sent to.
+//
+// Deprecated: use Start, with RunNow() and Stop() in place of the channels.
 func (j *Job) Done() (error, chan struct{}, chan struct{}) {
+	return j.Start(), j.skip, j.quit
+}
+
+// Run validates and starts the job as Start() does, but executes it on the
+// calling goroutine, blocking until it is exhausted, reaches its ending time or
+// Stop() is called from elsewhere. Executions never overlap as each one is
+// waited for before waiting for the next event. It returns the error Start()
+// would report, or nil once the job has ended.
+func (j *Job) Run() error {
+	if err := j.prepare(true); err != nil {
+		return err
+	}
+	j.loop()
+	return nil
+}
+
+// Validates the job and marks it as started, ready for the backend to take it.
+// Blocking jobs execute their task on the scheduling goroutine.
+func (j *Job) prepare(blocking bool) error {
+	j.lock.Lock()
+	defer j.lock.Unlock()
+
+	if j.started {
+		return ErrStarted
+	}
+
+	errs := append([]error(nil), j.errs...)
+	if j.task == nil {
+		errs = append(errs, errors.New("missing task, jobs without one deliver Ticks()"))
+	}
+	if j.times == 0 || j.times < -1 {
+		errs = append(errs, fmt.Errorf("%d is not a valid number of executions",
+			j.times))
+	} else if j.aux.selected && j.aux.kind == onceKind && j.times != 1 {
+		errs = append(errs, errors.New("single executions can not run several times"))
+	}
+	state, loadErr := j.load()
+	if loadErr != nil {
+		errs = append(errs, loadErr)
+	}
 	var (
-		err      error
 		schedule scheduler
+		err      error
+		aux      = j.aux
 	)
+	switch {
+	case j.parent != nil && blocking:
+		err = errors.New("dependent jobs are run by their parent")
+	case j.parent != nil && j.aux.selected:
+		err = errors.New("dependent jobs can not have a cadence")
+	case j.watchdog > 0 && (j.wheel != nil || j.parent != nil || blocking):
+		err = errors.New("watchdogs require the job's own goroutine")
+	case j.wheel != nil && blocking:
+		err = errors.New("blocking jobs run on the calling goroutine, not on a wheel")
+	case j.wheel != nil && j.wheel.isStopped():
+		err = errors.New("the wheel has been stopped")
+	// The cadence of a misused builder would only report the misuse again
+	case j.parent == nil && len(j.errs) == 0:
+		j.seed()
+		aux = j.aux
+		// A stored position counts from the anchor it was saved with
+		if j.store != nil && aux.start.IsZero() {
+			aux.start = state.Start
+			if aux.start.IsZero() {
+				aux.start = now()
+			}
+		}
+		schedule, err = aux.build()
+	}
+	if err != nil {
+		errs = append(errs, err)
+	}
+	if len(errs) > 0 {
+		return errors.Join(errs...)
+	}
 
-	switch j.aux.kind {
-	case periodicKind:
-		schedule, err = newPeriodic(j.aux.start, j.aux.end, j.aux.ammount,
-			j.aux.unit, j.aux.notInmediately)
-	case monthlyKind:
-		schedule, err = newMonthly(j.aux.start, j.aux.end, j.aux.ammount,
-			j.aux.notInmediately)
-	case yearlyKind:
-		schedule, err = newYearly(j.aux.start, j.aux.end, j.aux.ammount,
-			j.aux.notInmediately)
+	// The starting time is kept to allow rescheduling from it
+	j.aux.start = aux.start
+	if j.aux.start.IsZero() {
+		j.aux.start = now()
+	}
+	if j.store != nil {
+		j.restore(schedule, state)
 	}
+	j.schedule = schedule
+	j.blocking = blocking
+	j.started = true
+	return nil
+}
 
-	if err == nil {
-		j.schedule = schedule
+// Err returns the problems recorded by the builder so far, including attempts
+// to modify the job after it was scheduled
+func (j *Job) Err() error {
+	j.lock.Lock()
+	defer j.lock.Unlock()
 
-		go func(j *Job) {
-			var (
-				ok    bool
-				next  time.Duration
-				timer *time.Timer
-			)
-			for {
-				ok, next = j.schedule.next()
-				if !ok {
+	return errors.Join(j.errs...)
+}
+
+// Reports wether the builder can still be modified, recording an error if the
+// job has already been scheduled
+func (j *Job) mutable() bool {
+	j.lock.Lock()
+	defer j.lock.Unlock()
+
+	if j.started {
+		j.errs = append(j.errs, ErrStarted)
+		return false
+	}
+	return true
+}
+
+// Records a misuse of the builder to be reported by Start()
+func (j *Job) fail(err error) *Job {
+	j.lock.Lock()
+	defer j.lock.Unlock()
+
+	j.errs = append(j.errs, err)
+	return j
+}
+
+// Scheduling loop, runs in its own goroutine, or the one calling Run(), until
+// the job ends
+func (j *Job) loop() {
+	defer j.finish()
+	j.serve(0)
+}
+
+// Body of the scheduling loop, returns when the job ends or, if it is
+// supervised by a watchdog, once a newer generation has replaced it
+func (j *Job) serve(gen int) {
+	var (
+		ok    bool
+		next  time.Time
+		d     time.Duration
+		timer timer
+	)
+	for {
+		if j.replaced(gen) {
+			return
+		}
+		ok, next = j.advance()
+		if !ok {
+			return
+		}
+		d = j.delay(next)
+		if j.logger != nil {
+			j.logger.Printf("chronos: timer armed for %v", d)
+		}
+		timer = newTimer(d)
+		for waiting := true; waiting; {
+			select {
+			case <-j.quit:
+				timer.Stop()
+				// Stops meant for the goroutine that replaced this one
+				if j.replaced(gen) {
+					j.Stop()
 					return
 				}
-				timer = time.NewTimer(next)
-				select {
-				case <-j.quit:
+				if j.logger != nil {
+					j.logger.Printf("chronos: stopped")
+				}
+				return
+			case <-j.exhausted:
+				timer.Stop()
+				if j.logger != nil {
+					j.logger.Printf("chronos: completed, execution count exhausted")
+				}
+				return
+			case <-j.skip:
+				timer.Stop()
+				if j.logger != nil {
+					j.logger.Printf("chronos: fired on demand")
+				}
+				j.dispatch(now())
+				waiting = false
+			case <-j.reset:
+				// The new event may have already been taken by advance()
+				if !j.rearmed() {
+					continue
+				}
+				timer.Stop()
+				if j.logger != nil {
+					j.logger.Printf("chronos: rescheduled")
+				}
+				waiting = false
+			case <-timer.C():
+				// The wall clock may not have reached the event yet, as the
+				// timer is not affected when it jumps backwards
+				if d = j.delay(next); d > 0 {
+					if j.logger != nil {
+						j.logger.Printf("chronos: timer armed for %v", d)
+					}
+					timer.Reset(d)
+					continue
+				}
+				if j.replaced(gen) {
 					return
-				case <-j.skip:
-					go j.run()
-				case <-timer.C:
-					go j.run()
 				}
+				if j.logger != nil {
+					j.logger.Printf("chronos: fired")
+				}
+				j.dispatch(next)
+				waiting = false
 			}
-		}(j)
+		}
+	}
+}
+
+// Returns the next event of the job, or false if it has completed
+func (j *Job) advance() (bool, time.Time) {
+	ok, next, reason := j.upcoming()
+	if !ok {
+		if j.logger != nil {
+			j.logger.Printf("chronos: completed, %s", reason)
+		}
+		return false, next
+	}
+	if j.logger != nil {
+		j.logger.Printf("chronos: schedule computed, next at %v in %v",
+			next, next.Sub(now()))
+	}
+	return true, next
+}
+
+// Reports wether Reschedule computed an event that has not been taken yet
+func (j *Job) rearmed() bool {
+	j.lock.Lock()
+	defer j.lock.Unlock()
+
+	return j.rearm
+}
+
+// Releases the job once nothing else will be scheduled
+func (j *Job) finish() {
+	j.finishing.Do(func() {
+		// Dependent jobs can not be triggered once done is closed
+		j.lock.Lock()
+		j.nextRun = time.Time{}
+		close(j.done)
+		j.lock.Unlock()
+
+		if j.blocking {
+			j.complete()
+			return
+		}
+		go func() {
+			j.running.Wait()
+			j.complete()
+		}()
+	})
+}
+
+// Ends the dependent jobs and runs the OnComplete hook, once the last
+// execution has finished
+func (j *Job) complete() {
+	for _, d := range j.children() {
+		d.finish()
+	}
+	if j.ticks != nil {
+		close(j.ticks)
+	}
+	if j.completed != nil {
+		j.completed()
+	}
+}
+
+// Returns how long to wait for the next event, 0 if it is already due. Events
+// of the monotonic clock are measured with it and the rest with the wall
+// clock, so they never fire early. In drift-corrected mode the wall clock is
+// always used and long waits are split, so that a timer running late or early
+// with respect to the wall clock is corrected before firing
+func (j *Job) delay(next time.Time) time.Duration {
+	if !j.noDrift {
+		d := next.Sub(now())
+		j.lock.Lock()
+		m, ok := j.schedule.(monotonic)
+		j.lock.Unlock()
+		if ok {
+			if due, ok := m.due(next); ok {
+				d = due - elapsed()
+			}
+		}
+		if d > 0 {
+			return d
+		}
+		return 0
+	}
+
+	d := next.Round(0).Sub(now())
+	switch {
+	case d < time.Millisecond:
+		return 0
+	case d > driftCheck:
+		return driftCheck
+	}
+	return d
+}
+
+// Changing the cadence of a job
+
+// Anchor of the new cadence of a rescheduled job
+type Anchor int
+
+const (
+	FromStart Anchor = iota // Keeps the original starting time
+	FromNow                 // Starts the new cadence now
+)
+
+// Reschedule replaces the cadence of the job, which can be done while it is
+// running. apply receives a builder holding the starting and ending times, the
+// jitter and NotInmediately of the job but no cadence, which must be defined
+// again (b.Every(3).Minutes()). apply is called without holding the job, so it
+// can query it. The pending timer is cancelled and the next execution, already
+// reflected by NextRun(), is computed from the new cadence anchored as
+// requested. Executions so far and the number of times are kept. Jobs that
+// have already ended can not be rescheduled and report ErrEnded.
+func (j *Job) Reschedule(anchor Anchor, apply func(b *Job)) error {
+	j.lock.Lock()
+	if j.parent != nil {
+		j.lock.Unlock()
+		return errors.New("dependent jobs can not have a cadence")
+	}
+	b := &Job{aux: auxiliar{start: j.aux.start, end: j.aux.end,
+		notInmediately: j.aux.notInmediately, jitter: j.aux.jitter,
+		seed: j.aux.seed, seeded: j.aux.seeded}}
+	random := j.rand
+	j.lock.Unlock()
+
+	apply(b)
+	if len(b.errs) > 0 {
+		return errors.Join(b.errs...)
+	}
+	b.rand = random
+	b.seed()
+
+	j.lock.Lock()
+	aux := b.aux
+	started := j.started
+	if started {
+		select {
+		case <-j.done:
+			j.lock.Unlock()
+			return ErrEnded
+		default:
+		}
+		now := now()
+		if anchor == FromNow {
+			aux.start = now
+		}
+		// Events of the new cadence before now have already been replaced by
+		// the ones of the old cadence
+		if !aux.start.After(now) {
+			aux.notInmediately = true
+		}
+	}
+	schedule, err := aux.build()
+	if err != nil {
+		j.lock.Unlock()
+		return err
+	}
+
+	// The effective anchor is kept for Preview() and the state store
+	j.aux = b.aux
+	j.aux.start = aux.start
+	if started {
+		j.schedule = schedule
+		ok, next := j.schedule.next()
+		if !ok {
+			next = time.Time{}
+		}
+		j.nextRun = next
+		j.rearm = true
+		select {
+		case j.reset <- struct{}{}:
+		default:
+		}
+	}
+	j.lock.Unlock()
+
+	if started {
+		j.notify()
+	}
+	return nil
+}
+
+// Reset replaces the cadence of the job with a new period so that the next
+// execution happens one period from now, see Reschedule
+func (j *Job) Reset(amount int, unit time.Duration) error {
+	return j.Reschedule(FromNow, func(b *Job) {
+		b.Every(amount).duration(unit)
+	})
+}
+
+// Preview returns up to the next n executions of the job without affecting it,
+// or the error that Start() would report about the builder or its cadence
+func (j *Job) Preview(n int) ([]time.Time, error) {
+	j.lock.Lock()
+	if len(j.errs) > 0 {
+		j.lock.Unlock()
+		return nil, errors.Join(j.errs...)
+	}
+	// Dependent jobs have no events of their own
+	if j.parent != nil {
+		j.lock.Unlock()
+		return nil, nil
+	}
+	j.seed()
+	aux := j.aux.clone()
+	// Only the executions are shown
+	aux.closes = false
+	// Events before now have already happened for scheduled jobs
+	if j.started && !aux.start.After(now()) {
+		aux.notInmediately = true
+	}
+	if j.times != -1 && j.times-j.n < n {
+		n = j.times - j.n
+	}
+	j.lock.Unlock()
+
+	schedule, err := aux.build()
+	if err != nil {
+		return nil, err
+	}
+
+	var res []time.Time
+	for len(res) < n {
+		ok, next := schedule.next()
+		if !ok {
+			break
+		}
+		res = append(res, next)
+	}
+	return res, nil
+}
+
+// String returns a human readable description of the cadence of the job
+func (j *Job) String() string {
+	j.lock.Lock()
+	defer j.lock.Unlock()
+
+	return j.aux.describe()
+}
+
+// NextRun returns when the next execution is scheduled, zero if there is none
+func (j *Job) NextRun() time.Time {
+	j.lock.Lock()
+	defer j.lock.Unlock()
+
+	if j.ended(j.nextRun) != "" {
+		return time.Time{}
+	}
+	return j.nextRun
+}
+
+// Returns the next event, computing it unless Reschedule already did it, or
+// why the job has ended
+func (j *Job) upcoming() (bool, time.Time, string) {
+	j.lock.Lock()
+	defer j.lock.Unlock()
+
+	// Release the job as soon as the count is exhausted
+	if reason := j.ended(now()); reason != "" {
+		j.nextRun = time.Time{}
+		j.rearm = false
+		return false, j.nextRun, reason
+	}
+
+	if !j.rearm {
+		ok, next := j.schedule.next()
+		if !ok {
+			next = time.Time{}
+		}
+		j.nextRun = next
+	}
+	j.rearm = false
+	if j.nextRun.IsZero() {
+		return false, j.nextRun, "no more events scheduled"
 	}
+	if reason := j.ended(j.nextRun); reason != "" {
+		j.nextRun = time.Time{}
+		return false, j.nextRun, reason
+	}
+	return true, j.nextRun, ""
+}
+
+// Reports why the job is no longer active at the given time, either because
+// its count is exhausted or its ending time has passed, or "" if it is still
+// active. This is the only check deciding it, called while holding j.lock.
+func (j *Job) ended(at time.Time) string {
+	if j.times != -1 && j.n >= j.times {
+		return "execution count exhausted"
+	}
+	if !j.aux.end.IsZero() && !at.Before(j.aux.end) {
+		return "ending time reached"
+	}
+	return ""
+}
+
+// Controlling the scheduled job
+
+// Stop stops scheduling new executions without waiting for the in-flight one
+func (j *Job) Stop() {
+	select {
+	case j.quit <- struct{}{}:
+	default:
+	}
+	j.notify()
+
+	// Dependent jobs have no scheduling goroutine to be stopped
+	j.lock.Lock()
+	dependent := j.parent != nil && j.started
+	j.lock.Unlock()
+	if dependent {
+		j.finish()
+	}
+}
+
+// RunNow executes the task inmediately instead of waiting for the next event
+func (j *Job) RunNow() {
+	select {
+	case j.skip <- struct{}{}:
+	default:
+	}
+	j.notify()
+}
+
+// Shutdown stops scheduling new executions and waits for the in-flight one, if
+// any, to finish. If ctx expires first, the task's context is cancelled and
+// ctx.Err() is returned; the task itself is not interrupted.
+func (j *Job) Shutdown(ctx context.Context) error {
+	// A job that was never scheduled has nothing to wait for
+	j.lock.Lock()
+	started := j.started
+	j.lock.Unlock()
+	if !started {
+		return nil
+	}
+
+	j.Stop()
+
+	drained := make(chan struct{})
+	go func() {
+		<-j.done
+		j.running.Wait()
+		close(drained)
+	}()
+
+	select {
+	case <-drained:
+		return nil
+	case <-ctx.Done():
+		j.cancel()
+		return ctx.Err()
+	}
+}
+
+// Remaining returns the number of executions left, -1 meaning no limit
+func (j *Job) Remaining() int {
+	j.lock.Lock()
+	defer j.lock.Unlock()
+
+	if j.times == -1 {
+		return -1
+	}
+	return j.times - j.n
+}
+
+// Executions returns the number of times the task has been executed
+func (j *Job) Executions() int {
+	j.lock.Lock()
+	defer j.lock.Unlock()
+
+	return j.n
+}
+
+// Skipped returns the number of scheduled executions that were skipped
+func (j *Job) Skipped() int {
+	j.lock.Lock()
+	defer j.lock.Unlock()
 
-	return err, j.skip, j.quit
+	return j.skipped
+}
+
+// Executes the task for the event at the given time in a new goroutine, or a
+// worker of the wheel, keeping track of it. Blocking jobs and those delivering
+// ticks execute it right away.
+func (j *Job) dispatch(at time.Time) {
+	if j.closing(at) {
+		return
+	}
+
+	j.lock.Lock()
+	j.fired = at
+	// Keep the position of the fired event to be saved after the execution
+	if r, ok := j.schedule.(resumable); ok && j.store != nil {
+		j.index = r.position()
+	}
+	j.lock.Unlock()
+
+	j.running.Add(1)
+	if j.blocking || j.ticks != nil {
+		j.run()
+		return
+	}
+	if j.wheel != nil {
+		j.wheel.work <- j
+		return
+	}
+	go j.run()
+}
+
+// Lets the wheel, if any, know that one of the job's channels was signalled.
+// Jobs not started yet are left alone, the wheel takes them once they are.
+func (j *Job) notify() {
+	j.lock.Lock()
+	started := j.started
+	j.lock.Unlock()
+	if j.wheel != nil && started {
+		j.wheel.wake(j)
+	}
 }
 
 func (j *Job) run() {
+	defer j.running.Done()
+
+	j.execute(now(), false)
+}
+
+// Executes the task for the event due at the given time unless it is skipped.
+// An execution delayed by MinGap waits on its own goroutine without holding
+// j.mutex, so that neither the rest nor a worker of the wheel are blocked, and
+// is retried once the gap has passed. Only one of them waits at a time, those
+// due meanwhile count as skipped. Blocking jobs wait in place instead.
+func (j *Job) execute(due time.Time, delayed bool) {
 	j.mutex.Lock()
 	defer j.mutex.Unlock()
 
-	if j.times == -1 || j.n < j.times {
-		j.n++
-		j.task()
+	if delayed {
+		j.lock.Lock()
+		j.gapped = false
+		j.lock.Unlock()
+	}
+
+	// The counters only change while holding j.mutex
+	if j.Remaining() == 0 {
+		if j.logger != nil {
+			j.logger.Printf("chronos: skipped, execution count exhausted")
+		}
+		return
+	}
+	// Bursts are accounted once, not again when retried after the gap
+	if !delayed && !j.paced(due) {
+		j.lock.Lock()
+		j.skipped++
+		j.lock.Unlock()
+		return
+	}
+	if wait := j.gap(); wait > 0 {
+		if !j.blocking && !j.hold() {
+			return
+		}
+		if j.logger != nil {
+			j.logger.Printf("chronos: delayed %v by the minimum gap", wait)
+		}
+		if !j.blocking {
+			j.running.Add(1)
+			go func() {
+				defer j.running.Done()
+				if j.pause(wait) {
+					j.execute(due, true)
+				}
+			}()
+			return
+		}
+		if !j.pause(wait) {
+			return
+		}
+	}
+	if j.beforeRun != nil && !j.beforeRun() {
+		j.lock.Lock()
+		j.skipped++
+		j.lock.Unlock()
+		if j.logger != nil {
+			j.logger.Printf("chronos: skipped, vetoed by BeforeRun")
+		}
+		return
+	}
+
+	start := now()
+	j.lock.Lock()
+	j.n++
+	j.lastRun = start
+	exhausted := j.n == j.times
+	if exhausted {
+		close(j.exhausted)
+	}
+	j.lock.Unlock()
+	if exhausted {
+		j.notify()
+	}
+
+	// Dependent jobs also run when the task panics, which goes on
+	ok := false
+	defer func() {
+		j.chain(ok)
+	}()
+	j.task(j.ctx)
+	j.lock.Lock()
+	j.lastEnd = now()
+	ok = !j.failed
+	j.lock.Unlock()
+	if j.store != nil {
+		j.save()
+	}
+}
+
+// Applies MaxBurst to an execution that was due at the given time, reporting
+// wether it can go on. Called while holding j.mutex.
+func (j *Job) paced(due time.Time) bool {
+	j.lock.Lock()
+	late := due.Before(j.lastEnd)
+	if late {
+		j.burst++
+	} else {
+		j.burst = 0
+	}
+	burst := j.burst
+	j.lock.Unlock()
+
+	if late && j.maxBurst > 0 && burst > j.maxBurst {
+		if j.logger != nil {
+			j.logger.Printf("chronos: skipped, burst limit reached")
+		}
+		return false
+	}
+	return true
+}
+
+// Marks an execution as the one waiting for MinGap, reporting false and
+// counting it as skipped if there is already one
+func (j *Job) hold() bool {
+	j.lock.Lock()
+	gapped := j.gapped
+	if gapped {
+		j.skipped++
+	}
+	j.gapped = true
+	j.lock.Unlock()
+
+	if gapped && j.logger != nil {
+		j.logger.Printf("chronos: skipped, an execution is already waiting for the minimum gap")
+	}
+	return !gapped
+}
+
+// Returns how long an execution has to wait for MinGap to pass since the start
+// of the previous one
+func (j *Job) gap() time.Duration {
+	j.lock.Lock()
+	defer j.lock.Unlock()
+
+	if j.minGap <= 0 {
+		return 0
+	}
+	return j.minGap - now().Sub(j.lastRun)
+}
+
+// Waits for the given time, reporting false and counting the execution as
+// skipped if the job ends meanwhile
+func (j *Job) pause(wait time.Duration) bool {
+	timer := newTimer(wait)
+	defer timer.Stop()
+	select {
+	case <-timer.C():
+		return true
+	case <-j.done:
+		j.lock.Lock()
+		j.skipped++
+		j.lock.Unlock()
+		if j.logger != nil {
+			j.logger.Printf("chronos: skipped, stopped while delayed")
+		}
+		return false
 	}
 }
@@ -0,0 +1,746 @@
+package chronos
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestRun(t *testing.T) {
+	runs := 0
+	if err := Schedule(func() { runs++ }).Every(5).Milliseconds().NTimes(4).Run(); err != nil {
+		t.Fatal(err)
+	}
+	if runs != 4 {
+		t.Fatalf("ran %d times", runs)
+	}
+
+	j := Schedule(func() {}).Every(5).Milliseconds()
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		j.Stop()
+	}()
+	if err := j.Run(); err != nil {
+		t.Fatal(err)
+	}
+	if err := j.Run(); err != ErrStarted {
+		t.Fatalf("run again: %v", err)
+	}
+
+	w := NewWheel(1)
+	defer w.Stop()
+	if err := Schedule(func() {}).Every(5).Milliseconds().UsingWheel(w).Run(); err == nil {
+		t.Fatal("ran a job of a wheel on the calling goroutine")
+	}
+}
+
+// Rescheduling drops every part of the previous cadence
+func TestRescheduleCadence(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	c := useFakeClock(t, start)
+	nine := func(d time.Time) time.Time {
+		return time.Date(d.Year(), d.Month(), d.Day(), 9, 0, 0, 0, time.UTC)
+	}
+	jobs := []*Job{
+		Schedule(func() {}).EveryDayAt(nine).Offset(-30 * time.Minute),
+		Schedule(func() {}).Cron("0 9 * * *"),
+		Schedule(func() {}).Every(1).Hour().Aligned().Between("09:00", "10:00"),
+		Schedule(func() {}).Every(2).Weeks().ByISOWeek(),
+		Schedule(func() {}).Every(1).Day().AtTimes("09:00"),
+	}
+	for _, j := range jobs {
+		if err := j.Start(); err != nil {
+			t.Fatal(err)
+		}
+		c.WaitTimers(t, 1)
+		err := j.Reschedule(FromNow, func(b *Job) { b.Every(1).Hours() })
+		if err != nil {
+			t.Fatalf("rescheduling %v: %v", j, err)
+		}
+		if next := j.NextRun(); !next.Equal(start.Add(time.Hour)) {
+			t.Fatalf("rescheduled %v to %v", j, next)
+		}
+		j.Stop()
+		<-j.done
+	}
+}
+
+// The builder can query the job being rescheduled
+func TestRescheduleQuery(t *testing.T) {
+	useFakeClock(t, time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	w := NewWheel(1)
+	defer w.Stop()
+
+	for _, j := range []*Job{Schedule(func() {}).Every(10).Seconds(),
+		Schedule(func() {}).Every(10).Seconds().UsingWheel(w)} {
+		if err := j.Start(); err != nil {
+			t.Fatal(err)
+		}
+		done := make(chan error)
+		go func() {
+			done <- j.Reschedule(FromStart, func(b *Job) {
+				b.Every(2 * (j.Executions() + 1)).Seconds()
+			})
+		}()
+		select {
+		case err := <-done:
+			if err != nil {
+				t.Fatal(err)
+			}
+		case <-time.After(time.Second):
+			t.Fatal("rescheduling blocked")
+		}
+		j.Stop()
+		<-j.done
+	}
+}
+
+// Clones are modified without affecting the original job
+func TestClone(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	useFakeClock(t, start)
+	j := Schedule(func() {}).Every(1).Hour().At(start).Named("job")
+	c := j.Clone()
+	c.Every(2).Hours().At(start.Add(time.Minute)).Named("clone")
+	expectPreview(t, j, start, start.Add(time.Hour))
+	expectPreview(t, c, start.Add(time.Minute), start.Add(2*time.Hour+time.Minute))
+	if j.name != "job" || c.name != "clone" {
+		t.Fatalf("named %q and %q", j.name, c.name)
+	}
+
+	// Clones do not share the state of the original job
+	store := NewMemoryStore()
+	j = Schedule(func() {}).Every(1).Hour().Named("job").WithStateStore(store)
+	if err := j.Clone().Start(); err == nil {
+		t.Fatal("started a clone sharing the state of the original job")
+	}
+	c = j.Clone().Named("clone")
+	if err := c.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer c.Stop()
+	if j.Err() != nil || j.name != "job" || j.started {
+		t.Fatalf("starting the clone modified the original job")
+	}
+}
+
+// A 1h gap on a 1 minute schedule delays the executions without blocking the
+// only worker of the wheel, which keeps running other jobs
+func TestMinGap(t *testing.T) {
+	c := useFakeClock(t, time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	w := NewWheel(1)
+	defer w.Stop()
+
+	var (
+		starts = make(chan time.Time, 10)
+		others counter
+	)
+	j := Schedule(func() { starts <- now() }).Every(1).Minute().MinGap(time.Hour).
+		UsingWheel(w)
+	other := Schedule(others.inc).Every(1).Minute().NotInmediately().UsingWheel(w)
+	for _, job := range []*Job{j, other} {
+		if err := job.Start(); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	c.WaitTimers(t, 1)
+	c.Advance(time.Hour + 30*time.Minute)
+	eventually(t, func() bool { return others.count() == 90 })
+	// Only one execution waits for the gap, the events due meanwhile of
+	// minutes 0 to 90 are skipped right away
+	eventually(t, func() bool { return j.Skipped() == 88 })
+	j.Stop()
+	<-j.done
+	close(starts)
+
+	var previous time.Time
+	runs := 0
+	for start := range starts {
+		if runs > 0 && start.Sub(previous) < time.Hour {
+			t.Fatalf("started at %v, %v after the previous one", start, start.Sub(previous))
+		}
+		previous = start
+		runs++
+	}
+	if runs != 2 || j.Executions() != 2 {
+		t.Fatalf("ran %d times, %d executions", runs, j.Executions())
+	}
+	// The one waiting is skipped once the job stops
+	eventually(t, func() bool { return j.Executions()+j.Skipped() == 91 })
+}
+
+// Shutdown waits for the execution in flight unless its deadline expires first
+func TestShutdown(t *testing.T) {
+	slow := func(started chan<- struct{}, cancelled *counter) func(context.Context) {
+		return func(ctx context.Context) {
+			started <- struct{}{}
+			select {
+			case <-time.After(200 * time.Millisecond):
+			case <-ctx.Done():
+				cancelled.inc()
+				time.Sleep(20 * time.Millisecond)
+			}
+		}
+	}
+
+	for _, test := range []struct {
+		deadline time.Duration
+		err      error
+	}{
+		{500 * time.Millisecond, nil},
+		{50 * time.Millisecond, context.DeadlineExceeded},
+	} {
+		var cancelled counter
+		started := make(chan struct{}, 1)
+		j := ScheduleContext(slow(started, &cancelled)).Every(1).Hour()
+		if err := j.Start(); err != nil {
+			t.Fatal(err)
+		}
+		<-started
+
+		ctx, cancel := context.WithTimeout(context.Background(), test.deadline)
+		err := j.Shutdown(ctx)
+		cancel()
+		if err != test.err {
+			t.Fatalf("shutdown with a %v deadline: %v", test.deadline, err)
+		}
+		// Once drained, the job is consistent and shuts down right away
+		if err := j.Shutdown(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+		// The task was told to give up only when the deadline expired
+		if expired := test.err != nil; (cancelled.count() == 1) != expired {
+			t.Fatalf("task cancelled %d times", cancelled.count())
+		}
+		if j.Executions() != 1 || !j.NextRun().IsZero() {
+			t.Fatalf("%d executions, next at %v", j.Executions(), j.NextRun())
+		}
+	}
+}
+
+func TestShutdownAll(t *testing.T) {
+	m := NewManager()
+	started := make(chan struct{}, 3)
+	for i := 0; i < 3; i++ {
+		m.Schedule(func() {
+			started <- struct{}{}
+			time.Sleep(100 * time.Millisecond)
+		}).Every(1).Hour().Start()
+	}
+	for i := 0; i < 3; i++ {
+		<-started
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := m.ShutdownAll(ctx); err != context.DeadlineExceeded {
+		t.Fatalf("shutdown with a short deadline: %v", err)
+	}
+	if err := m.ShutdownAll(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestNTimes(t *testing.T) {
+	tests := []struct {
+		name string
+		job  func(f func()) *Job
+		// Minutes of the executions
+		runs []int
+	}{
+		{"once", func(f func()) *Job { return Schedule(f).Every(1).Minute().Once() },
+			[]int{0}},
+		{"once not inmediately", func(f func()) *Job {
+			return Schedule(f).Every(1).Minute().Once().NotInmediately()
+		}, []int{1}},
+		{"twice", func(f func()) *Job { return Schedule(f).Every(1).Minute().Twice() },
+			[]int{0, 1}},
+		{"3 times not inmediately", func(f func()) *Job {
+			return Schedule(f).Every(1).Minute().NTimes(3).NotInmediately()
+		}, []int{1, 2, 3}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+			c := useFakeClock(t, start)
+			runs := make(chan time.Time, 10)
+			j := test.job(func() { runs <- now() })
+			if err := j.Start(); err != nil {
+				t.Fatal(err)
+			}
+			c.WaitJob(t, j)
+			c.Advance(5 * time.Minute)
+			// Exhausted jobs end right away
+			<-j.done
+			close(runs)
+			i := 0
+			for run := range runs {
+				if i >= len(test.runs) || !run.Equal(start.Add(time.Duration(test.runs[i])*time.Minute)) {
+					t.Fatalf("run %d at %v", i+1, run)
+				}
+				i++
+			}
+			if i != len(test.runs) || j.Remaining() != 0 {
+				t.Fatalf("ran %d times, %d remaining", i, j.Remaining())
+			}
+		})
+	}
+
+	if err := Schedule(func() {}).Every(1).Minute().NTimes(0).Start(); err == nil {
+		t.Fatal("started a job with no executions")
+	}
+}
+
+// While an execution stalls a 1 minute schedule for an hour the events due
+// meanwhile wait for it, and only MaxBurst of them run late once it ends
+func TestMaxBurst(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	c := useFakeClock(t, start)
+	var runs counter
+	release := make(chan struct{})
+	j := Schedule(func() {
+		if runs.count() == 0 {
+			<-release
+		}
+		runs.inc()
+	}).Every(1).Minute().MaxBurst(3)
+	if err := j.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer j.Stop()
+
+	c.WaitTimers(t, 1)
+	c.Advance(59*time.Minute + 30*time.Second)
+	close(release)
+	// The events of minutes 1 to 59 were due before the stalled one ended
+	eventually(t, func() bool { return j.Executions()+j.Skipped() == 60 })
+	if runs.count() != 4 || j.Skipped() != 56 {
+		t.Fatalf("ran %d times, skipped %d", runs.count(), j.Skipped())
+	}
+	// The cadence resumes afterwards
+	c.Advance(2 * time.Minute)
+	eventually(t, func() bool { return runs.count() == 6 })
+	if j.Skipped() != 56 {
+		t.Fatalf("skipped %d", j.Skipped())
+	}
+}
+
+// Executions on demand count as any other
+func TestNTimesRunNow(t *testing.T) {
+	c := useFakeClock(t, time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	var runs counter
+	j := Schedule(runs.inc).Every(1).Minute().Once().NotInmediately()
+	if err := j.Start(); err != nil {
+		t.Fatal(err)
+	}
+	c.WaitTimers(t, 1)
+	j.RunNow()
+	<-j.done
+	c.Advance(5 * time.Minute)
+	if runs.count() != 1 || j.Remaining() != 0 {
+		t.Fatalf("ran %d times, %d remaining", runs.count(), j.Remaining())
+	}
+
+	// Through the channel returned by Done
+	runs = counter{}
+	start := c.now()
+	j = Schedule(runs.inc).Every(1).Minute().Twice().NotInmediately()
+	err, skip, _ := j.Done()
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.WaitTimers(t, 1)
+	skip <- struct{}{}
+	eventually(t, func() bool { return runs.count() == 1 })
+	if j.Remaining() != 1 {
+		t.Fatalf("%d remaining", j.Remaining())
+	}
+	// The wait skipped was the one for the first event
+	c.WaitTimers(t, 1)
+	if next := j.NextRun(); !next.Equal(start.Add(2 * time.Minute)) {
+		t.Fatalf("next run at %v", next)
+	}
+	c.Advance(2 * time.Minute)
+	<-j.done
+	if runs.count() != 2 {
+		t.Fatalf("ran %d times", runs.count())
+	}
+}
+
+// Slow tasks do not push the events of a drift-corrected job off its grid
+func TestNoDrift(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	c := useFakeClock(t, start)
+	var runs []time.Time
+	// Executions block the scheduling loop while they take their time
+	j := Schedule(func() {
+		runs = append(runs, now())
+		c.Advance(700*time.Millisecond + time.Duration(len(runs))*time.Millisecond)
+	}).Every(2).Seconds().NTimes(5).NoDrift()
+	errc := make(chan error, 1)
+	go func() { errc <- j.Run() }()
+
+	for i := 1; i < 5; i++ {
+		c.WaitTimers(t, 1)
+		c.Advance(j.NextRun().Sub(c.now()))
+	}
+	if err := <-errc; err != nil {
+		t.Fatal(err)
+	}
+	if len(runs) != 5 {
+		t.Fatalf("ran %d times", len(runs))
+	}
+	for k, run := range runs {
+		want := start.Add(time.Duration(k) * 2 * time.Second)
+		if d := run.Sub(want); d < -time.Millisecond || d > time.Millisecond {
+			t.Fatalf("run %d at %v, %v off the grid", k+1, run, d)
+		}
+	}
+}
+
+// Resetting the period mid-flight keeps the executions and the count limit
+func TestReset(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	c := useFakeClock(t, start)
+	runs := make(chan time.Time, 10)
+	release := make(chan struct{})
+	j := Schedule(func() {
+		runs <- now()
+		// The second execution is still running when the period is reset
+		if len(runs) == 2 {
+			<-release
+		}
+	}).Every(10).Seconds().NTimes(6)
+	if err := j.Start(); err != nil {
+		t.Fatal(err)
+	}
+	c.WaitTimers(t, 1)
+	c.Advance(15 * time.Second)
+	eventually(t, func() bool { return len(runs) == 2 })
+
+	armed := c.Armed()
+	if err := j.Reset(2, time.Second); err != nil {
+		t.Fatal(err)
+	}
+	close(release)
+	if next := j.NextRun(); !next.Equal(start.Add(17 * time.Second)) {
+		t.Fatalf("next run at %v", next)
+	}
+	if j.Executions() != 2 || j.Remaining() != 4 {
+		t.Fatalf("%d executions, %d remaining", j.Executions(), j.Remaining())
+	}
+	// The timer of the old period is replaced
+	eventually(t, func() bool { return c.Armed() > armed })
+	c.Advance(time.Minute)
+	<-j.done
+	close(runs)
+
+	want := []int{0, 10, 17, 19, 21, 23}
+	i := 0
+	for run := range runs {
+		if i >= len(want) || !run.Equal(start.Add(time.Duration(want[i])*time.Second)) {
+			t.Fatalf("run %d at %v", i+1, run)
+		}
+		i++
+	}
+	if i != len(want) {
+		t.Fatalf("ran %d times", i)
+	}
+}
+
+// Jobs end with whichever comes first, their count or their ending time
+func TestCountAndEnd(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name      string
+		times     int
+		end       time.Duration
+		runs      int
+		remaining int
+	}{
+		{"count first", 3, 10 * time.Minute, 3, 0},
+		{"end first", 10, 2*time.Minute + 30*time.Second, 3, 7},
+		{"end at the last event", 3, 2 * time.Minute, 2, 1},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			c := useFakeClock(t, start)
+			var runs counter
+			j := Schedule(runs.inc).Every(1).Minute().NTimes(test.times).
+				Until(start.Add(test.end))
+			if err := j.Start(); err != nil {
+				t.Fatal(err)
+			}
+			c.WaitJob(t, j)
+			c.Advance(15 * time.Minute)
+			<-j.done
+			j.running.Wait()
+			if runs.count() != test.runs || j.Remaining() != test.remaining {
+				t.Fatalf("ran %d times, %d remaining", runs.count(), j.Remaining())
+			}
+			if next := j.NextRun(); !next.IsZero() {
+				t.Fatalf("ended job runs next at %v", next)
+			}
+		})
+	}
+}
+
+// The anchor of a reset job is the one previewed and saved, and ended jobs can
+// not be rescheduled
+func TestResetAnchor(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	c := useFakeClock(t, start)
+	store := NewMemoryStore()
+	j := Schedule(func() {}).Every(1).Minute().Named("job").WithStateStore(store)
+	if err := j.Start(); err != nil {
+		t.Fatal(err)
+	}
+	c.WaitTimers(t, 1)
+	c.Advance(3 * time.Minute)
+	if err := j.Reset(7, time.Minute); err != nil {
+		t.Fatal(err)
+	}
+	minutes := func(n int) time.Time { return start.Add(time.Duration(n) * time.Minute) }
+	if next := j.NextRun(); !next.Equal(minutes(10)) {
+		t.Fatalf("next run at %v", next)
+	}
+	expectPreview(t, j, minutes(10), minutes(17), minutes(24))
+	c.Advance(7 * time.Minute)
+	eventually(t, func() bool {
+		state, err := store.Load("job")
+		return err == nil && state.Executions == 5 && state.Start.Equal(minutes(3))
+	})
+
+	j.Stop()
+	<-j.done
+	if err := j.Reset(1, time.Minute); err != ErrEnded {
+		t.Fatalf("rescheduled an ended job: %v", err)
+	}
+	if next := j.NextRun(); !next.IsZero() {
+		t.Fatalf("ended job runs next at %v", next)
+	}
+}
+
+// Units can be selected again but not replaced, and misuses of the builder are
+// reported once both by Start and Preview
+func TestBuilderErrors(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 30, 0, 0, time.UTC)
+	useFakeClock(t, start)
+	expectPreview(t, Schedule(func() {}).Every(6).Hours().OnTheHour(),
+		start.Add(5*time.Hour+30*time.Minute), start.Add(11*time.Hour+30*time.Minute))
+	expectPreview(t, Schedule(func() {}).Every(2).Quarters().EveryQuarterOn(1, 15),
+		time.Date(2024, 2, 15, 0, 30, 0, 0, time.UTC),
+		time.Date(2024, 8, 15, 0, 30, 0, 0, time.UTC))
+
+	for _, j := range []*Job{Schedule(func() {}).Every(1).Months().Seconds(),
+		Schedule(func() {}).Every(1, 2).Minutes(),
+		Schedule(func() {}).Every(-1).Minutes()} {
+		if events, err := j.Preview(1); err == nil {
+			t.Fatalf("previewed %v of a misused builder", events)
+		}
+		err := j.Start()
+		if err == nil {
+			t.Fatal("started a misused builder")
+		}
+		if errs := err.(interface{ Unwrap() []error }).Unwrap(); len(errs) != 1 {
+			t.Fatalf("reported %q", errs)
+		}
+	}
+}
+
+// Jobs run on the union of their cadences, running once when several are due
+func TestOr(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	c := useFakeClock(t, start)
+	minutes := func(n int) time.Time { return start.Add(time.Duration(n) * time.Minute) }
+	j := Schedule(func() {}).Every(1).Hour().
+		Or(Schedule(nil).Every(90).Minutes()).
+		Or(Schedule(nil).Every(1).Day().AtTimes("02:15"))
+	expectPreview(t, j, minutes(0), minutes(60), minutes(90), minutes(120),
+		minutes(135), minutes(180), minutes(240), minutes(270))
+
+	// Only the cadences due at an instant advance
+	runs := make(chan time.Time, 10)
+	j = Schedule(func() { runs <- now() }).Every(2).Minutes().NotInmediately().
+		Or(Schedule(nil).Every(3).Minutes().NotInmediately()).NTimes(4)
+	if err := j.Start(); err != nil {
+		t.Fatal(err)
+	}
+	c.WaitTimers(t, 1)
+	c.Advance(10 * time.Minute)
+	<-j.done
+	j.running.Wait()
+	close(runs)
+	want := []time.Time{minutes(2), minutes(3), minutes(4), minutes(6)}
+	i := 0
+	for run := range runs {
+		if i >= len(want) || !run.Equal(want[i]) {
+			t.Fatalf("run %d at %v", i+1, run)
+		}
+		i++
+	}
+	if i != len(want) {
+		t.Fatalf("ran %d times", i)
+	}
+	if next := j.NextRun(); !next.IsZero() {
+		t.Fatalf("next run at %v", next)
+	}
+}
+
+// Seeding the cadences added to a job leaves the other job untouched
+func TestOrCopy(t *testing.T) {
+	useFakeClock(t, time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	other := Schedule(func() {}).Every(1).Hour().
+		Or(Schedule(nil).Every(1).Minute().Jitter(time.Second))
+	j := Schedule(func() {}).Every(1).Day().Or(other)
+	if _, err := j.Preview(1); err != nil {
+		t.Fatal(err)
+	}
+	if other.aux.alternatives[0].seeded {
+		t.Fatal("seeded the cadences of the other job")
+	}
+}
+
+func TestScheduleAt(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	c := useFakeClock(t, start)
+	past := start.Add(-time.Hour)
+	tests := []struct {
+		name string
+		job  func(f func()) *Job
+		runs int
+	}{
+		{"future", func(f func()) *Job { return ScheduleAfter(time.Minute, f) }, 1},
+		{"past", func(f func()) *Job { return ScheduleAt(past, f) }, 1},
+		{"run if past", func(f func()) *Job { return ScheduleAt(past, f).RunIfPast() }, 1},
+		{"skip if past", func(f func()) *Job { return ScheduleAt(past, f).SkipIfPast() }, 0},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var runs counter
+			completed := make(chan struct{})
+			j := test.job(runs.inc).OnComplete(func() { close(completed) })
+			if err := j.Start(); err != nil {
+				t.Fatal(err)
+			}
+			c.WaitJob(t, j)
+			c.Advance(time.Minute)
+			<-completed
+			if runs.count() != test.runs {
+				t.Fatalf("ran %d times", runs.count())
+			}
+		})
+	}
+
+	// Stopped before firing, it completes without running
+	var runs counter
+	completed := make(chan struct{})
+	j := ScheduleAfter(time.Hour, runs.inc).OnComplete(func() { close(completed) })
+	if err := j.Start(); err != nil {
+		t.Fatal(err)
+	}
+	c.WaitTimers(t, 1)
+	j.Stop()
+	<-completed
+	c.Advance(2 * time.Hour)
+	if runs.count() != 0 {
+		t.Fatalf("ran %d times after being stopped", runs.count())
+	}
+}
+
+// Options of single executions are rejected elsewhere, and the other way round
+func TestScheduleAtOptions(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	useFakeClock(t, start)
+	for _, j := range []*Job{
+		Schedule(func() {}).Every(1).Minute().SkipIfPast(),
+		Schedule(func() {}).Every(1).Minute().RunIfPast(),
+		ScheduleAt(start, func() {}).NTimes(3),
+	} {
+		if err := j.Start(); err == nil {
+			t.Fatalf("started %v", j)
+		}
+	}
+	j := ScheduleAt(start.Add(time.Hour), func() {}).Once().SkipIfPast()
+	if err := j.Start(); err != nil {
+		t.Fatal(err)
+	}
+	j.Stop()
+}
+
+// Vetoed executions count as skipped, not as executions
+func TestBeforeRun(t *testing.T) {
+	c := useFakeClock(t, time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	var runs, calls counter
+	j := Schedule(runs.inc).Every(1).Minute().NTimes(2).BeforeRun(func() bool {
+		calls.inc()
+		return calls.count()%2 == 0
+	})
+	if err := j.Start(); err != nil {
+		t.Fatal(err)
+	}
+	c.WaitTimers(t, 1)
+	c.Advance(5 * time.Minute)
+	<-j.done
+	j.running.Wait()
+	if runs.count() != 2 || calls.count() != 4 || j.Skipped() != 2 {
+		t.Fatalf("ran %d times, vetoed %d", runs.count(), j.Skipped())
+	}
+}
+
+// The same function runs with the argument of each job
+func TestScheduleArg(t *testing.T) {
+	c := useFakeClock(t, time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	args := make(chan string, 4)
+	f := func(arg string) { args <- arg }
+	for _, arg := range []string{"a", "b"} {
+		if err := ScheduleArg(arg, f).Every(1).Minute().Twice().Start(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	c.WaitTimers(t, 2)
+	c.Advance(time.Minute)
+	eventually(t, func() bool { return len(args) == 4 })
+	close(args)
+	received := map[string]int{}
+	for arg := range args {
+		received[arg]++
+	}
+	if received["a"] != 2 || received["b"] != 2 {
+		t.Fatalf("received %v", received)
+	}
+}
+
+// AtClock starts today unless the time of the day has already passed
+func TestAtClock(t *testing.T) {
+	date := func(day, hour, min int, loc *time.Location) time.Time {
+		return time.Date(2024, 1, day, hour, min, 0, 0, loc)
+	}
+	useFakeClock(t, date(1, 10, 0, time.UTC))
+	madrid, err := time.LoadLocation("Europe/Madrid")
+	if err != nil {
+		t.Skip(err)
+	}
+	tests := []struct {
+		name     string
+		clock    time.Time
+		expected time.Time
+	}{
+		{"later today", date(5, 11, 0, time.UTC), date(1, 11, 0, time.UTC)},
+		{"now", date(5, 10, 0, time.UTC), date(1, 10, 0, time.UTC)},
+		{"passed", date(5, 9, 30, time.UTC), date(2, 9, 30, time.UTC)},
+		// 11:00 in Madrid
+		{"passed in its location", date(5, 10, 30, madrid), date(2, 10, 30, madrid)},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			expectPreview(t, Schedule(func() {}).Every(1).Day().AtClock(test.clock),
+				test.expected, test.expected.AddDate(0, 0, 1))
+		})
+	}
+
+	if err := Schedule(func() {}).Every(1).Day().AtClockString("9am").Start(); err == nil {
+		t.Fatal("started at an invalid time of the day")
+	}
+}
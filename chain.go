@@ -0,0 +1,115 @@
+package chronos
+
+import (
+	"errors"
+	"sync"
+)
+
+// Mutex to protect the dependent jobs of every job, so that cycles can be
+// detected across them
+var dependencies sync.Mutex
+
+// Defining the job as dependent on another one, so that instead of following
+// a cadence it runs right after each successful execution of its parent, or
+// of every one if RunOnFailure is defined. The dependent job is scheduled with
+// Start() as any other, and ends when its parent does. Dependencies can be
+// chained but not form cycles.
+
+func (j *Job) After(parent *Job) *Job {
+	if !j.mutable() {
+		return j
+	}
+
+	dependencies.Lock()
+	defer dependencies.Unlock()
+
+	if j.parent != nil {
+		return j.fail(errors.New("dependent jobs can only have one parent"))
+	}
+	if parent == j || parent.dependsOn(j) {
+		return j.fail(errors.New("dependency cycle"))
+	}
+	j.parent = parent
+	parent.followers = append(parent.followers, j)
+	return j
+}
+
+// Then returns a new job running the given task after each successful
+// execution of this one, which can be configured as any other and must be
+// scheduled with Start(), see After
+
+func (j *Job) Then(f func()) *Job {
+	return Schedule(f).After(j)
+}
+
+// Defining that a dependent job also runs after the executions of its parent
+// that failed, those whose task panicked or, for typed jobs, returned an error
+
+func (j *Job) RunOnFailure() *Job {
+	if !j.mutable() {
+		return j
+	}
+	j.onFailure = true
+	return j
+}
+
+// Reports wether the job depends, directly or not, on the given one. Called
+// while holding the dependencies mutex.
+func (j *Job) dependsOn(other *Job) bool {
+	for p := j.parent; p != nil; p = p.parent {
+		if p == other {
+			return true
+		}
+	}
+	return false
+}
+
+// Returns the dependent jobs
+func (j *Job) children() []*Job {
+	dependencies.Lock()
+	defer dependencies.Unlock()
+
+	return append([]*Job(nil), j.followers...)
+}
+
+// Runs the dependent jobs after an execution, which failed unless ok
+func (j *Job) chain(ok bool) {
+	for _, d := range j.children() {
+		if ok || d.onFailure {
+			d.trigger()
+		}
+	}
+}
+
+// Executes a dependent job on the calling goroutine, unless it has not been
+// scheduled yet or has ended
+func (j *Job) trigger() {
+	j.lock.Lock()
+	select {
+	case <-j.done:
+		j.lock.Unlock()
+		return
+	default:
+	}
+	if !j.started {
+		j.lock.Unlock()
+		return
+	}
+	j.running.Add(1)
+	j.lock.Unlock()
+
+	select {
+	case <-j.quit:
+		j.running.Done()
+		j.finish()
+		return
+	default:
+	}
+	j.run()
+
+	select {
+	case <-j.exhausted:
+		j.finish()
+	default:
+	}
+}
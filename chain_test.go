@@ -0,0 +1,110 @@
+package chronos
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestAfterCycle(t *testing.T) {
+	a, b, c := Schedule(func() {}), Schedule(func() {}), Schedule(func() {})
+	b.After(a)
+	c.After(b)
+	if err := a.After(c).Err(); err == nil {
+		t.Fatal("accepted the cycle A->B->C->A")
+	}
+	if err := a.After(a).Err(); err == nil {
+		t.Fatal("accepted a job depending on itself")
+	}
+	if err := c.After(a).Err(); err == nil {
+		t.Fatal("accepted a second parent")
+	}
+}
+
+// Dependent jobs run after their parent, and end with it
+func TestAfterStop(t *testing.T) {
+	c := useFakeClock(t, time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	var parents, children, grandchildren counter
+	parent := Schedule(parents.inc).Every(1).Minute().NotInmediately()
+	child := parent.Then(children.inc)
+	grandchild := child.Then(grandchildren.inc)
+	for _, j := range []*Job{grandchild, child, parent} {
+		if err := j.Start(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	c.WaitTimers(t, 1)
+	c.Advance(2 * time.Minute)
+	eventually(t, func() bool { return grandchildren.count() == 2 })
+	if parents.count() != 2 || children.count() != 2 {
+		t.Fatalf("ran %d parents and %d children", parents.count(), children.count())
+	}
+
+	parent.Stop()
+	for _, j := range []*Job{parent, child, grandchild} {
+		select {
+		case <-j.done:
+		case <-time.After(time.Second):
+			t.Fatal("the chain did not end with its parent")
+		}
+	}
+}
+
+// Failed executions of typed jobs only trigger the dependent jobs that run on
+// failure
+func TestRunOnFailure(t *testing.T) {
+	c := useFakeClock(t, time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	var runs, successes, all counter
+	parent := ScheduleResult(func() (int, error) {
+		runs.inc()
+		if runs.count()%2 == 1 {
+			return 0, errors.New("odd run")
+		}
+		return runs.count(), nil
+	})
+	parent.Every(1).Minute().NTimes(4)
+	success := parent.Then(successes.inc)
+	always := parent.Then(all.inc).RunOnFailure()
+	for _, j := range []*Job{success, always} {
+		if err := j.Start(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	results, err := parent.Start()
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.WaitJob(t, parent.Job)
+	c.Advance(5 * time.Minute)
+	failures := 0
+	for r := range results {
+		if r.Err != nil {
+			failures++
+		}
+	}
+	if failures != 2 || successes.count() != 2 || all.count() != 4 {
+		t.Fatalf("%d failures, ran %d times on success and %d always", failures,
+			successes.count(), all.count())
+	}
+}
+
+func TestManagerAfter(t *testing.T) {
+	c := useFakeClock(t, time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	m := NewManager()
+	var runs counter
+	parent := m.Schedule(func() {}).Every(1).Minute().NotInmediately()
+	child := m.After(parent, runs.inc)
+	for _, j := range []*Job{child, parent} {
+		if err := j.Start(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	c.WaitTimers(t, 1)
+	c.Advance(time.Minute)
+	eventually(t, func() bool { return runs.count() == 1 })
+
+	// The dependent job is registered, so stopping all of them ends it
+	m.StopAll()
+	<-child.done
+	<-parent.done
+}
@@ -0,0 +1,86 @@
+package chronos
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// Source of the time of the package, which tests replace to control it
+type clock interface {
+	// Returns the wall clock time
+	now() time.Time
+	// Returns the monotonic clock time, since an arbitrary origin
+	elapsed() time.Duration
+	// Returns a timer firing once the monotonic clock has advanced d
+	timer(d time.Duration) timer
+}
+
+// Timer of a clock, see time.Timer
+type timer interface {
+	C() <-chan time.Time
+	Stop() bool
+	Reset(d time.Duration) bool
+}
+
+// Clock in use, holds a clockHolder
+var clocks atomic.Value
+
+// Wrapper storing any clock in an atomic.Value
+type clockHolder struct {
+	clock
+}
+
+func init() {
+	setClock(systemClock{})
+}
+
+// Replaces the clock of the package
+func setClock(c clock) {
+	clocks.Store(clockHolder{c})
+}
+
+// Returns the current wall clock time
+func now() time.Time {
+	return clocks.Load().(clockHolder).now()
+}
+
+// Returns the current monotonic clock time
+func elapsed() time.Duration {
+	return clocks.Load().(clockHolder).elapsed()
+}
+
+// Returns a new timer of the clock in use
+func newTimer(d time.Duration) timer {
+	return clocks.Load().(clockHolder).timer(d)
+}
+
+// Origin of the monotonic clock of the system
+var origin = time.Now()
+
+// Clock of the system
+type systemClock struct{}
+
+// Implements clock.now()
+func (systemClock) now() time.Time {
+	return time.Now().Round(0)
+}
+
+// Implements clock.elapsed()
+func (systemClock) elapsed() time.Duration {
+	return time.Since(origin)
+}
+
+// Implements clock.timer()
+func (systemClock) timer(d time.Duration) timer {
+	return systemTimer{time.NewTimer(d)}
+}
+
+// Timer of the system clock
+type systemTimer struct {
+	*time.Timer
+}
+
+// Implements timer.C()
+func (t systemTimer) C() <-chan time.Time {
+	return t.Timer.C
+}
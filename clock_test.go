@@ -0,0 +1,310 @@
+package chronos
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// Clock of the tests, only moving when told to
+type fakeClock struct {
+	mutex  sync.Mutex
+	wall   time.Time
+	mono   time.Duration
+	timers map[*fakeTimer]bool
+	// Number of times a timer has been armed
+	armed int
+}
+
+// Replaces the clock of the package by a fake one showing start, until the end
+// of the test
+func useFakeClock(t testing.TB, start time.Time) *fakeClock {
+	c := &fakeClock{wall: start, timers: make(map[*fakeTimer]bool)}
+	setClock(c)
+	t.Cleanup(func() { setClock(systemClock{}) })
+	return c
+}
+
+// Implements clock.now()
+func (c *fakeClock) now() time.Time {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return c.wall
+}
+
+// Implements clock.elapsed()
+func (c *fakeClock) elapsed() time.Duration {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return c.mono
+}
+
+// Implements clock.timer()
+func (c *fakeClock) timer(d time.Duration) timer {
+	t := &fakeTimer{clock: c, c: make(chan time.Time, 1)}
+	t.Reset(d)
+	return t
+}
+
+// Moves both the wall and the monotonic clocks forward d, firing the timers
+// one deadline at a time and letting the jobs react to each of them
+func (c *fakeClock) Advance(d time.Duration) {
+	c.mutex.Lock()
+	target := c.mono + d
+	c.mutex.Unlock()
+	for {
+		c.mutex.Lock()
+		var first *fakeTimer
+		for t := range c.timers {
+			if t.at <= target && (first == nil || t.at < first.at) {
+				first = t
+			}
+		}
+		if first == nil {
+			// A task may have moved the clock past the target meanwhile
+			if target > c.mono {
+				c.wall = c.wall.Add(target - c.mono)
+				c.mono = target
+			}
+			c.mutex.Unlock()
+			return
+		}
+		if first.at > c.mono {
+			c.wall = c.wall.Add(first.at - c.mono)
+			c.mono = first.at
+		}
+		armed := c.armed
+		first.fire()
+		c.mutex.Unlock()
+		c.settle(armed)
+	}
+}
+
+// Moves the wall clock d, as when the system time is changed, leaving the
+// monotonic clock and thus the timers untouched
+func (c *fakeClock) Jump(d time.Duration) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.wall = c.wall.Add(d)
+}
+
+// Waits until at least n timers are pending
+func (c *fakeClock) WaitTimers(t testing.TB, n int) {
+	t.Helper()
+	eventually(t, func() bool {
+		c.mutex.Lock()
+		defer c.mutex.Unlock()
+		return len(c.timers) >= n
+	})
+}
+
+// Returns the number of times a timer has been armed
+func (c *fakeClock) Armed() int {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return c.armed
+}
+
+// Waits until the job is waiting for its next event, or has ended
+func (c *fakeClock) WaitJob(t testing.TB, j *Job) {
+	t.Helper()
+	eventually(t, func() bool {
+		select {
+		case <-j.done:
+			return true
+		default:
+		}
+		c.mutex.Lock()
+		defer c.mutex.Unlock()
+		return len(c.timers) > 0
+	})
+}
+
+// Gives the goroutines woken by a timer some time to arm the next one
+func (c *fakeClock) settle(armed int) {
+	for deadline := time.Now().Add(100 * time.Millisecond); time.Now().Before(deadline); {
+		c.mutex.Lock()
+		done := c.armed != armed
+		c.mutex.Unlock()
+		if done {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+	// Let the executions dispatched meanwhile start
+	time.Sleep(5 * time.Millisecond)
+}
+
+// Timer of a fakeClock
+type fakeTimer struct {
+	clock *fakeClock
+	c     chan time.Time
+	at    time.Duration
+}
+
+// Implements timer.C()
+func (t *fakeTimer) C() <-chan time.Time {
+	return t.c
+}
+
+// Implements timer.Stop()
+func (t *fakeTimer) Stop() bool {
+	t.clock.mutex.Lock()
+	defer t.clock.mutex.Unlock()
+	active := t.clock.timers[t]
+	delete(t.clock.timers, t)
+	return active
+}
+
+// Implements timer.Reset()
+func (t *fakeTimer) Reset(d time.Duration) bool {
+	t.clock.mutex.Lock()
+	defer t.clock.mutex.Unlock()
+	active := t.clock.timers[t]
+	t.at = t.clock.mono + d
+	t.clock.timers[t] = true
+	t.clock.armed++
+	if d <= 0 {
+		t.fire()
+	}
+	return active
+}
+
+// Sends the current time through the channel, with the clock locked
+func (t *fakeTimer) fire() {
+	delete(t.clock.timers, t)
+	select {
+	case t.c <- t.clock.wall:
+	default:
+	}
+}
+
+// Fails the test unless cond becomes true within a second
+func eventually(t testing.TB, cond func() bool) {
+	t.Helper()
+	for deadline := time.Now().Add(time.Second); !cond(); {
+		if time.Now().After(deadline) {
+			t.Fatal("condition not met in time")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+// Task counting its executions
+type counter struct {
+	n int32
+}
+
+func (c *counter) inc() {
+	atomic.AddInt32(&c.n, 1)
+}
+
+func (c *counter) count() int {
+	return int(atomic.LoadInt32(&c.n))
+}
+
+func TestFakeClock(t *testing.T) {
+	c := useFakeClock(t, time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	var runs counter
+	j := Schedule(runs.inc).Every(1).Minute().NotInmediately()
+	if err := j.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer j.Stop()
+
+	c.WaitTimers(t, 1)
+	c.Advance(59 * time.Second)
+	if runs.count() != 0 {
+		t.Fatalf("ran %d times before the first minute", runs.count())
+	}
+	c.Advance(2*time.Minute + time.Second)
+	eventually(t, func() bool { return runs.count() == 3 })
+	if next := j.NextRun(); !next.Equal(time.Date(2024, 1, 1, 0, 4, 0, 0, time.UTC)) {
+		t.Fatalf("next run at %v", next)
+	}
+}
+
+// Periods are measured with the monotonic clock, so steps of the wall clock
+// neither burst nor stall them
+func TestClockJumpPeriodic(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	c := useFakeClock(t, start)
+	var runs counter
+	j := Schedule(runs.inc).Every(1).Minute().NotInmediately()
+	if err := j.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer j.Stop()
+
+	c.WaitTimers(t, 1)
+	c.Advance(time.Minute)
+	eventually(t, func() bool { return runs.count() == 1 })
+	for i, step := range []time.Duration{time.Hour, -2 * time.Hour, time.Hour} {
+		c.Jump(step)
+		c.Advance(time.Minute)
+		eventually(t, func() bool { return runs.count() == i+2 })
+		if next := j.NextRun(); !next.Equal(start.Add(time.Duration(i+3) * time.Minute)) {
+			t.Fatalf("next run at %v after stepping %v", next, step)
+		}
+	}
+	time.Sleep(10 * time.Millisecond)
+	if runs.count() != 4 {
+		t.Fatalf("ran %d times", runs.count())
+	}
+}
+
+// Events of the wall clock never fire early nor twice when it steps back
+func TestClockJumpWall(t *testing.T) {
+	c := useFakeClock(t, time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	var runs counter
+	j := Schedule(runs.inc).Every(1).Day().AtTimes("00:01")
+	if err := j.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer j.Stop()
+
+	c.WaitTimers(t, 1)
+	c.Advance(time.Minute)
+	eventually(t, func() bool { return runs.count() == 1 })
+	// 00:01 shows again on the wall clock
+	c.Jump(-time.Hour)
+	c.Advance(2 * time.Hour)
+	time.Sleep(10 * time.Millisecond)
+	if runs.count() != 1 {
+		t.Fatalf("ran %d times", runs.count())
+	}
+	next := j.NextRun()
+	if !next.Equal(time.Date(2024, 1, 2, 0, 1, 0, 0, time.UTC)) {
+		t.Fatalf("next run at %v", next)
+	}
+	// The wall clock must reach the event, which takes an hour longer
+	c.Advance(22 * time.Hour)
+	time.Sleep(10 * time.Millisecond)
+	if runs.count() != 1 {
+		t.Fatalf("ran %d times before the wall clock reached %v", runs.count(), next)
+	}
+	c.Advance(time.Hour)
+	eventually(t, func() bool { return runs.count() == 2 })
+}
+
+// Periodic events keep the location of their start
+func TestPeriodicLocation(t *testing.T) {
+	useFakeClock(t, time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	for _, name := range []string{"Europe/Madrid", "America/New_York"} {
+		loc, err := time.LoadLocation(name)
+		if err != nil {
+			t.Skip(err)
+		}
+		events, err := Schedule(func() {}).Every(1).Hour().
+			At(time.Date(2024, 1, 1, 9, 0, 0, 0, loc)).Preview(2)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, event := range events {
+			if event.Location() != loc {
+				t.Fatalf("event %v not in %v", event, loc)
+			}
+		}
+	}
+}
@@ -0,0 +1,195 @@
+package chronos
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Limits of each field of a cron expression: seconds, minutes, hours, days of
+// the month, months and days of the week (0 or 7 being Sunday)
+var cronLimits = [6][2]int{{0, 59}, {0, 59}, {0, 23}, {1, 31}, {1, 12}, {0, 7}}
+
+// Parsed cron expression, each field is a bitset of the accepted values
+type cronSpec struct {
+	expr    string    // Original expression
+	fields  [6]uint64 // Accepted values of each field
+	anyDay, // Wether the day of the month field is *
+	anyWeekday bool // Wether the day of the week field is *
+}
+
+// Parses a cron expression with 5 fields (minutes, hours, days of the month,
+// months and days of the week) or 6 fields, the leading one being seconds. Each
+// field accepts *, values, ranges (a-b) and steps (*/s, a-b/s or a/s) separated
+// by commas. 5 field expressions run at second 0.
+func parseCron(expr string) (*cronSpec, error) {
+	fields := strings.Fields(expr)
+	switch len(fields) {
+	case 5:
+		fields = append([]string{"0"}, fields...)
+	case 6:
+	default:
+		return nil, fmt.Errorf("cron expression %q must have 5 or 6 fields", expr)
+	}
+
+	spec := &cronSpec{expr: expr, anyDay: fields[3] == "*",
+		anyWeekday: fields[5] == "*"}
+	for i, field := range fields {
+		bits, err := parseCronField(field, cronLimits[i][0], cronLimits[i][1])
+		if err != nil {
+			return nil, fmt.Errorf("cron expression %q: %v", expr, err)
+		}
+		spec.fields[i] = bits
+	}
+	// Sunday can be written both as 0 and 7
+	if spec.fields[5]&(1<<7) != 0 {
+		spec.fields[5] |= 1
+	}
+	return spec, nil
+}
+
+// Parses a field of a cron expression into a bitset of the accepted values
+func parseCronField(field string, min, max int) (uint64, error) {
+	var bits uint64
+	for _, part := range strings.Split(field, ",") {
+		values, step := part, 1
+		if i := strings.Index(part, "/"); i >= 0 {
+			var err error
+			values = part[:i]
+			if step, err = strconv.Atoi(part[i+1:]); err != nil || step <= 0 {
+				return 0, fmt.Errorf("invalid step in %q", part)
+			}
+		}
+
+		first, last := min, max
+		switch i := strings.Index(values, "-"); {
+		case values == "*":
+		case i >= 0:
+			var err1, err2 error
+			first, err1 = strconv.Atoi(values[:i])
+			last, err2 = strconv.Atoi(values[i+1:])
+			if err1 != nil || err2 != nil {
+				return 0, fmt.Errorf("invalid range %q", values)
+			}
+		default:
+			var err error
+			if first, err = strconv.Atoi(values); err != nil {
+				return 0, fmt.Errorf("invalid value %q", values)
+			}
+			// A single value without step only accepts itself
+			if step == 1 && values == part {
+				last = first
+			}
+		}
+		if first < min || last > max || first > last {
+			return 0, fmt.Errorf("%q out of range %d-%d", part, min, max)
+		}
+
+		for value := first; value <= last; value += step {
+			bits |= 1 << uint(value)
+		}
+	}
+	return bits, nil
+}
+
+// Reports wether a field accepts the given value
+func (c *cronSpec) accepts(field, value int) bool {
+	return c.fields[field]&(1<<uint(value)) != 0
+}
+
+// Reports wether the day of t is accepted. As in cron, when both day fields
+// are restricted, matching any of them is enough
+func (c *cronSpec) acceptsDay(t time.Time) bool {
+	day, weekday := c.accepts(3, t.Day()), c.accepts(5, int(t.Weekday()))
+	if c.anyDay || c.anyWeekday {
+		return day && weekday
+	}
+	return day || weekday
+}
+
+// Returns the first accepted time after t, at second granularity, or false if
+// there is none in the following years
+func (c *cronSpec) after(t time.Time) (bool, time.Time) {
+	loc := t.Location()
+	t = t.Truncate(time.Second).Add(time.Second)
+	for limit := t.AddDate(5, 0, 0); t.Before(limit); {
+		year, month, day := t.Date()
+		switch {
+		case !c.accepts(4, int(month)):
+			t = time.Date(year, month+1, 1, 0, 0, 0, 0, loc)
+		case !c.acceptsDay(t):
+			t = time.Date(year, month, day+1, 0, 0, 0, 0, loc)
+		case !c.accepts(2, t.Hour()):
+			t = t.Add(time.Hour - time.Duration(t.Minute())*time.Minute -
+				time.Duration(t.Second())*time.Second)
+		case !c.accepts(1, t.Minute()):
+			t = t.Add(time.Minute - time.Duration(t.Second())*time.Second)
+		case !c.accepts(0, t.Second()):
+			t = t.Add(time.Second)
+		default:
+			return true, t
+		}
+	}
+	return false, time.Time{}
+}
+
+// Defining the cadence with a cron expression of 5 fields, or 6 fields when
+// seconds are included, see parseCron. Only the starting and ending times are
+// used along with it.
+
+func (j *Job) Cron(expr string) *Job {
+	spec, err := parseCron(expr)
+	if err != nil {
+		return j.fail(err)
+	}
+	if !j.period(cronKind, 0, 0).mutable() {
+		return j
+	}
+	j.aux.cron = spec
+	return j
+}
+
+// Periods defined by a cron expression
+type cron struct {
+	spec  *cronSpec // Parsed cron expression
+	last, // Previous event, or the instant before the start time
+	end time.Time // End time, zero value means no end
+}
+
+// Constructor
+func newCron(spec *cronSpec, start, end time.Time) (*cron, error) {
+	// Check the input is valid
+	if spec == nil {
+		return nil, errors.New("missing cron expression")
+	}
+	// If no start time was assigned, use current time
+	if start.IsZero() {
+		start = now()
+	}
+	// Check the ending time, if any, is after the starting time
+	if !end.IsZero() && !end.After(start) {
+		return nil, errors.New("end time not after start time")
+	}
+
+	return &cron{spec: spec, last: start.Add(-time.Nanosecond), end: end}, nil
+}
+
+// Implements scheduler.next()
+func (s *cron) next() (bool, time.Time) {
+	// Events before now are skipped
+	from := s.last
+	if now := now(); from.Before(now) {
+		from = now
+	}
+
+	ok, next := s.spec.after(from)
+	if !ok {
+		return false, next
+	}
+	s.last = next
+
+	// Check if the end date has arrived
+	return s.end.IsZero() || next.Before(s.end), next
+}
@@ -0,0 +1,54 @@
+package chronos
+
+import (
+	"testing"
+	"time"
+)
+
+// 5 field expressions run at second 0, 6 field ones at the seconds given
+func TestCronSeconds(t *testing.T) {
+	date := func(min, sec int) time.Time {
+		return time.Date(2024, 1, 1, 0, min, sec, 0, time.UTC)
+	}
+	useFakeClock(t, date(0, 50))
+	tests := []struct {
+		expr     string
+		expected []time.Time
+	}{
+		{"* * * * *", []time.Time{date(1, 0), date(2, 0), date(3, 0)}},
+		{"0 * * * * *", []time.Time{date(1, 0), date(2, 0), date(3, 0)}},
+		{"*/30 * * * * *", []time.Time{date(1, 0), date(1, 30), date(2, 0)}},
+		{"55 * * * * *", []time.Time{date(0, 55), date(1, 55), date(2, 55)}},
+		{"0-59/20 1 * * * *", []time.Time{date(1, 0), date(1, 20), date(1, 40)}},
+	}
+	for _, test := range tests {
+		t.Run(test.expr, func(t *testing.T) {
+			expectPreview(t, Schedule(func() {}).Cron(test.expr), test.expected...)
+		})
+	}
+
+	for _, expr := range []string{"* * * *", "* * * * * * *", "60 * * * * *"} {
+		if _, err := Schedule(func() {}).Cron(expr).Preview(1); err == nil {
+			t.Errorf("accepted %q", expr)
+		}
+	}
+}
+
+// Running across minute boundaries, both kinds fire at second 0 and only the
+// 6 field expression fires within the minute
+func TestCronBoundary(t *testing.T) {
+	c := useFakeClock(t, time.Date(2024, 1, 1, 0, 0, 50, 0, time.UTC))
+	var minutes, halves counter
+	for _, j := range []*Job{Schedule(minutes.inc).Cron("* * * * *"),
+		Schedule(halves.inc).Cron("*/30 * * * * *")} {
+		if err := j.Start(); err != nil {
+			t.Fatal(err)
+		}
+		defer j.Stop()
+	}
+	c.WaitTimers(t, 2)
+	c.Advance(10 * time.Second)
+	eventually(t, func() bool { return minutes.count() == 1 && halves.count() == 1 })
+	c.Advance(2 * time.Minute)
+	eventually(t, func() bool { return minutes.count() == 3 && halves.count() == 5 })
+}
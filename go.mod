@@ -0,0 +1,3 @@
+module github.com/Adirio/chronos
+
+go 1.20
@@ -0,0 +1,74 @@
+package chronos
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"time"
+)
+
+// ISO-8601 duration with integer values, e.g. P1Y2M, P2W or PT15M
+var iso8601 = regexp.MustCompile(`^P(?:(\d+)Y)?(?:(\d+)M)?(?:(\d+)W)?(?:(\d+)D)?` +
+	`(?:T(?:(\d+)H)?(?:(\d+)M)?(?:(\d+)S)?)?$`)
+
+// Units of each component of an ISO-8601 duration after the months
+var iso8601Units = []time.Duration{Week, Day, time.Hour, time.Minute, time.Second}
+
+// Job construction with task assignment and a period given as an ISO-8601
+// duration. Years and months are scheduled monthly (or yearly when there are no
+// months), so P1Y6M runs every 18 months, while weeks, days, hours, minutes and
+// seconds are scheduled periodically. Both groups can not be mixed.
+func ScheduleISO8601(period string, f func()) (*Job, error) {
+	match := iso8601.FindStringSubmatch(period)
+	if match == nil || period == "P" || period[len(period)-1] == 'T' {
+		return nil, fmt.Errorf("malformed ISO-8601 duration %q", period)
+	}
+
+	values := make([]int, len(match)-1)
+	for i, value := range match[1:] {
+		if value == "" {
+			continue
+		}
+		var err error
+		if values[i], err = strconv.Atoi(value); err != nil {
+			return nil, fmt.Errorf("malformed ISO-8601 duration %q: %v", period, err)
+		}
+	}
+	years, months := values[0], values[1]
+	if years > (math.MaxInt-months)/12 {
+		return nil, fmt.Errorf("ISO-8601 duration %q is too long", period)
+	}
+
+	var d time.Duration
+	for i, unit := range iso8601Units {
+		value := time.Duration(values[i+2])
+		if value > (math.MaxInt64-d)/unit {
+			return nil, fmt.Errorf("ISO-8601 duration %q is too long", period)
+		}
+		d += value * unit
+	}
+
+	j := Schedule(f)
+	switch {
+	case (years != 0 || months != 0) && d != 0:
+		return nil, fmt.Errorf("ISO-8601 duration %q mixes years or months with "+
+			"shorter units", period)
+	case months != 0:
+		j.Every(12*years + months).Months()
+	case years != 0:
+		j.Every(years).Years()
+	case d != 0:
+		// Use the largest unit that divides the period
+		for _, unit := range iso8601Units {
+			if d%unit == 0 {
+				j.Every(int(d / unit)).duration(unit)
+				break
+			}
+		}
+	default:
+		return nil, errors.New("0 is not a valid period")
+	}
+	return j, j.Err()
+}
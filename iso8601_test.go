@@ -0,0 +1,40 @@
+package chronos
+
+import (
+	"testing"
+	"time"
+)
+
+func TestISO8601(t *testing.T) {
+	start := time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC)
+	useFakeClock(t, start)
+	tests := []struct {
+		period   string
+		expected []time.Time
+	}{
+		{"P1Y6M", []time.Time{start, time.Date(2025, 7, 31, 0, 0, 0, 0, time.UTC),
+			time.Date(2027, 1, 31, 0, 0, 0, 0, time.UTC)}},
+		{"P2Y", []time.Time{start, time.Date(2026, 1, 31, 0, 0, 0, 0, time.UTC)}},
+		{"PT1H30M", []time.Time{start, start.Add(90 * time.Minute),
+			start.Add(3 * time.Hour)}},
+		{"P1DT12H", []time.Time{start, start.Add(36 * time.Hour)}},
+		{"P2W", []time.Time{start, start.Add(2 * Week)}},
+	}
+	for _, test := range tests {
+		t.Run(test.period, func(t *testing.T) {
+			j, err := ScheduleISO8601(test.period, func() {})
+			if err != nil {
+				t.Fatal(err)
+			}
+			expectPreview(t, j, test.expected...)
+		})
+	}
+
+	for _, period := range []string{"", "P", "PT", "1H", "P1H", "PT1.5H", "P-1D",
+		"P1YT1H", "P0D", "PT99999999999999H", "P999999999999999999Y",
+		"PT9223372037S", "PT2562047H60M"} {
+		if j, err := ScheduleISO8601(period, func() {}); err == nil {
+			t.Errorf("accepted %q as %v", period, j)
+		}
+	}
+}
@@ -0,0 +1,18 @@
+package chronos
+
+// Logger receives the scheduling decisions of a job, such as when the next
+// event was computed, when the timer was armed, fired or stopped and why an
+// execution was skipped. *log.Logger satisfies it.
+type Logger interface {
+	Printf(format string, v ...interface{})
+}
+
+// Defining where to report scheduling events, no logging is done by default
+
+func (j *Job) WithLogger(l Logger) *Job {
+	if !j.mutable() {
+		return j
+	}
+	j.logger = l
+	return j
+}
@@ -0,0 +1,56 @@
+package chronos
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+)
+
+// Logger keeping the messages it receives
+type recordingLogger struct {
+	mutex    sync.Mutex
+	messages []string
+}
+
+func (l *recordingLogger) Printf(format string, v ...interface{}) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	l.messages = append(l.messages, fmt.Sprintf(format, v...))
+}
+
+// Events of a blocking job running 3 times, in the order they happen
+func TestLogger(t *testing.T) {
+	c := useFakeClock(t, time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	l := &recordingLogger{}
+	j := Schedule(func() {}).Every(1).Minute().NTimes(3).WithLogger(l)
+	errc := make(chan error, 1)
+	go func() { errc <- j.Run() }()
+	for i := 1; i < 3; i++ {
+		c.WaitTimers(t, 1)
+		c.Advance(time.Minute)
+	}
+	if err := <-errc; err != nil {
+		t.Fatal(err)
+	}
+	want := []string{
+		"chronos: schedule computed, next at 2024-01-01 00:00:00 +0000 UTC in 0s",
+		"chronos: timer armed for 0s",
+		"chronos: fired",
+		"chronos: schedule computed, next at 2024-01-01 00:01:00 +0000 UTC in 1m0s",
+		"chronos: timer armed for 1m0s",
+		"chronos: fired",
+		"chronos: schedule computed, next at 2024-01-01 00:02:00 +0000 UTC in 1m0s",
+		"chronos: timer armed for 1m0s",
+		"chronos: fired",
+		"chronos: completed, execution count exhausted",
+	}
+	if len(l.messages) != len(want) {
+		t.Fatalf("logged %q", l.messages)
+	}
+	for i := range want {
+		if l.messages[i] != want[i] {
+			t.Fatalf("logged %q instead of %q", l.messages[i], want[i])
+		}
+	}
+}
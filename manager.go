@@ -0,0 +1,161 @@
+package chronos
+
+import (
+	"context"
+	"sync"
+)
+
+// Manager groups several jobs so that they can be handled together
+type Manager struct {
+	jobs    []*Job     // Registered jobs
+	pruneAt int        // Number of registered jobs at which the ended ones are dropped
+	wheel   *Wheel     // Wheel scheduling the jobs, nil for a goroutine each
+	mutex   sync.Mutex // Mutex to protect the registered jobs
+}
+
+// Minimum number of registered jobs before dropping the ended ones
+const managerPrune = 16
+
+// Option configuring a manager
+type ManagerOption func(*Manager)
+
+// Schedules the jobs constructed by the manager on the given wheel
+func WithWheel(w *Wheel) ManagerOption {
+	return func(m *Manager) {
+		m.wheel = w
+	}
+}
+
+// Manager construction
+
+func NewManager(options ...ManagerOption) *Manager {
+	m := &Manager{}
+	for _, option := range options {
+		option(m)
+	}
+	return m
+}
+
+// Job construction with task assignment, registering the job in the manager
+
+func (m *Manager) Schedule(f func()) *Job {
+	return m.Add(m.backend(Schedule(f)))
+}
+
+func (m *Manager) ScheduleContext(f func(context.Context)) *Job {
+	return m.Add(m.backend(ScheduleContext(f)))
+}
+
+// Assigns the manager's wheel, if any, to a newly constructed job
+func (m *Manager) backend(j *Job) *Job {
+	if m.wheel != nil {
+		j.UsingWheel(m.wheel)
+	}
+	return j
+}
+
+// After registers a new job running the given task after each successful
+// execution of the parent, see Job.After
+func (m *Manager) After(parent *Job, f func()) *Job {
+	return m.Add(Schedule(f).After(parent))
+}
+
+// Registers an already constructed job in the manager. Jobs that have ended
+// are released as new ones are registered.
+func (m *Manager) Add(j *Job) *Job {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if len(m.jobs) >= m.pruneAt {
+		m.prune()
+	}
+	m.jobs = append(m.jobs, j)
+	return j
+}
+
+// Drops the registered jobs that have ended. Called while holding m.mutex.
+func (m *Manager) prune() {
+	jobs := m.jobs[:0]
+	for _, j := range m.jobs {
+		select {
+		case <-j.done:
+		default:
+			jobs = append(jobs, j)
+		}
+	}
+	// Let the dropped jobs be collected
+	for i := len(jobs); i < len(m.jobs); i++ {
+		m.jobs[i] = nil
+	}
+	m.jobs = jobs
+	// Pruning again once the jobs double keeps Add amortized constant
+	m.pruneAt = 2 * len(jobs)
+	if m.pruneAt < managerPrune {
+		m.pruneAt = managerPrune
+	}
+}
+
+// StopAll calls Job.Stop on every registered job without waiting for them
+func (m *Manager) StopAll() {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	m.prune()
+	for _, j := range m.jobs {
+		j.Stop()
+	}
+}
+
+// ShutdownAll calls Job.Shutdown concurrently on every registered job and waits
+// for all of them, returning ctx.Err() if any of them did not drain in time.
+func (m *Manager) ShutdownAll(ctx context.Context) error {
+	m.mutex.Lock()
+	m.prune()
+	jobs := append([]*Job(nil), m.jobs...)
+	m.mutex.Unlock()
+
+	errs := make(chan error, len(jobs))
+	for _, j := range jobs {
+		go func(j *Job) {
+			errs <- j.Shutdown(ctx)
+		}(j)
+	}
+
+	var err error
+	for range jobs {
+		if e := <-errs; e != nil {
+			err = e
+		}
+	}
+	return err
+}
+
+// Default manager used by the package level functions, lazily constructed
+var (
+	defaultManager *Manager
+	defaultOnce    sync.Once
+)
+
+// Returns the default manager
+func Default() *Manager {
+	defaultOnce.Do(func() {
+		defaultManager = NewManager()
+	})
+	return defaultManager
+}
+
+// Job construction on the default manager with task assignment and period size,
+// e.g. chronos.Every(f, 5).Seconds().Start()
+func Every(f func(), times ...int) *Job {
+	return Default().Schedule(f).Every(times...)
+}
+
+// Stops every job of the default manager, see Manager.StopAll
+func StopAll() {
+	Default().StopAll()
+}
+
+// Shuts down every job of the default manager, see Manager.ShutdownAll
+func ShutdownAll(ctx context.Context) error {
+	return Default().ShutdownAll(ctx)
+}
@@ -0,0 +1,42 @@
+package chronos
+
+import (
+	"testing"
+	"time"
+)
+
+// Jobs that have ended are released by the manager
+func TestManagerPrune(t *testing.T) {
+	c := useFakeClock(t, time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	m := NewManager()
+	pending := m.Schedule(func() {}).Every(1).Hour().NotInmediately()
+	if err := pending.Start(); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 100; i++ {
+		j := m.Schedule(func() {}).Every(1).Minute().Once()
+		if err := j.Start(); err != nil {
+			t.Fatal(err)
+		}
+		<-j.done
+	}
+	unstarted := m.Schedule(func() {})
+	c.Advance(time.Minute)
+
+	m.mutex.Lock()
+	jobs := len(m.jobs)
+	m.mutex.Unlock()
+	if jobs > managerPrune {
+		t.Fatalf("kept %d jobs", jobs)
+	}
+
+	// Jobs still running or not started yet are kept
+	pending.Stop()
+	<-pending.done
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.prune()
+	if len(m.jobs) != 1 || m.jobs[0] != unstarted {
+		t.Fatalf("kept %v", m.jobs)
+	}
+}
@@ -0,0 +1,120 @@
+package chronos
+
+import (
+	crand "crypto/rand"
+	"encoding/binary"
+	"errors"
+	"math/rand"
+	"sync"
+	"time"
+)
+
+var (
+	// Source of the randomness of every job without its own, see WithRand
+	random = rand.New(rand.NewSource(secureSeed()))
+	// Mutex to protect the sources, which are not safe for concurrent use
+	randMutex sync.Mutex
+)
+
+// Returns a seed read from the secure random number generator of the system
+func secureSeed() int64 {
+	var b [8]byte
+	if _, err := crand.Read(b[:]); err != nil {
+		return time.Now().UnixNano()
+	}
+	return int64(binary.LittleEndian.Uint64(b[:]))
+}
+
+// SetRandSource replaces the source of randomness of the package, which is
+// securely seeded by default, e.g. to make jittered jobs reproducible in tests.
+// Jobs that already took their seed are not affected.
+func SetRandSource(src rand.Source) {
+	randMutex.Lock()
+	defer randMutex.Unlock()
+
+	random = rand.New(src)
+}
+
+// Defining the source of randomness of the job instead of the package one
+
+func (j *Job) WithRand(r *rand.Rand) *Job {
+	if !j.mutable() {
+		return j
+	}
+	j.rand = r
+	return j
+}
+
+// Defining a random delay between 0 and max added to each event. The delay of
+// each event is always the same, so Preview() shows the actual executions.
+
+func (j *Job) Jitter(max time.Duration) *Job {
+	if max <= 0 {
+		return j.fail(errors.New("jitter must be positive"))
+	}
+	if j.mutable() {
+		j.aux.jitter = max
+	}
+	return j
+}
+
+// Takes the seeds of the jitter of the job's cadences from its source of
+// randomness, once. Called while holding j.lock.
+func (j *Job) seed() {
+	randMutex.Lock()
+	defer randMutex.Unlock()
+
+	r := j.rand
+	if r == nil {
+		r = random
+	}
+	for _, a := range append([]*auxiliar{&j.aux}, alternatives(&j.aux)...) {
+		if a.jitter > 0 && !a.seeded {
+			a.seed = r.Int63()
+			a.seeded = true
+		}
+	}
+}
+
+// Returns the cadences added to the given one by Or(), recursively
+func alternatives(a *auxiliar) []*auxiliar {
+	var res []*auxiliar
+	for i := range a.alternatives {
+		res = append(res, &a.alternatives[i])
+		res = append(res, alternatives(&a.alternatives[i])...)
+	}
+	return res
+}
+
+// Scheduler delaying each event of another one by a random amount derived from
+// a seed and the event itself
+type jittered struct {
+	schedule scheduler     // Scheduler whose events are delayed
+	max      time.Duration // Exclusive upper limit of the delays
+	seed     int64         // Seed of the delays
+}
+
+// Implements seeker.seek()
+func (s *jittered) seek(t time.Time) {
+	if seeker, ok := s.schedule.(seeker); ok {
+		seeker.seek(t.Add(-s.max))
+	}
+}
+
+// Implements monotonic.due()
+func (s *jittered) due(t time.Time) (time.Duration, bool) {
+	if m, ok := s.schedule.(monotonic); ok {
+		return m.due(t)
+	}
+	return 0, false
+}
+
+// Implements scheduler.next()
+func (s *jittered) next() (bool, time.Time) {
+	ok, next := s.schedule.next()
+	if next.IsZero() {
+		return ok, next
+	}
+	r := rand.New(rand.NewSource(s.seed ^ next.UnixNano()))
+	return ok, next.Add(time.Duration(r.Int63n(int64(s.max))))
+}
@@ -0,0 +1,48 @@
+package chronos
+
+import (
+	"math/rand"
+	"testing"
+	"time"
+)
+
+// Jittered jobs drawing from sources with the same seed preview the same events
+func TestJitterReproducible(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	useFakeClock(t, start)
+	t.Cleanup(func() { SetRandSource(rand.NewSource(secureSeed())) })
+
+	previews := map[string]func() *Job{
+		"WithRand": func() *Job {
+			return Schedule(func() {}).Every(1).Hour().Jitter(time.Minute).
+				WithRand(rand.New(rand.NewSource(42)))
+		},
+		"SetRandSource": func() *Job {
+			SetRandSource(rand.NewSource(42))
+			return Schedule(func() {}).Every(1).Hour().Jitter(time.Minute)
+		},
+	}
+	for name, job := range previews {
+		t.Run(name, func(t *testing.T) {
+			j := job()
+			events, err := j.Preview(10)
+			if err != nil {
+				t.Fatal(err)
+			}
+			jittered := false
+			for i, event := range events {
+				delay := event.Sub(start.Add(time.Duration(i) * time.Hour))
+				if delay < 0 || delay >= time.Minute {
+					t.Fatalf("event %v delayed %v", event, delay)
+				}
+				jittered = jittered || delay > 0
+			}
+			if !jittered {
+				t.Fatal("no event was delayed")
+			}
+			// Both another job and the same one again
+			expectPreview(t, job(), events...)
+			expectPreview(t, j, events...)
+		})
+	}
+}
@@ -0,0 +1,136 @@
+package chronos
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Size of the results buffer unless Buffer() is called
+const defaultResultBuffer = 16
+
+// Result of an execution of the task of a TypedJob
+type Result[T any] struct {
+	Value T         // Value returned by the task
+	Err   error     // Error returned by the task
+	Run   int       // Number of the execution, starting at 1
+	Start time.Time // When the execution started
+	End   time.Time // When the execution ended
+}
+
+// TypedJob is a Job whose task produces a value and an error, which are
+// delivered through the channel returned by Start(). The builder methods of Job
+// return the *Job, so the typed job is kept to start it:
+//
+//	t := chronos.ScheduleResult(f)
+//	t.Every(5).Seconds().NTimes(3)
+//	results, err := t.Start()
+type TypedJob[T any] struct {
+	*Job
+	task     func() (T, error) // Task producing the results
+	results  chan Result[T]    // Results of the executions
+	buffer   int               // Size of the results buffer
+	starting sync.Mutex        // Mutex to serialize the calls to Start()
+}
+
+// Job construction with a task that produces a result, see TypedJob
+func ScheduleResult[T any](f func() (T, error)) *TypedJob[T] {
+	t := &TypedJob[T]{task: f, buffer: defaultResultBuffer}
+	t.Job = ScheduleContext(t.run)
+	return t
+}
+
+// Executes the task delivering its result
+func (t *TypedJob[T]) run(context.Context) {
+	start := now()
+	value, err := t.task()
+	t.Job.lock.Lock()
+	t.Job.failed = err != nil
+	t.Job.lock.Unlock()
+	t.send(Result[T]{Value: value, Err: err, Run: t.Executions(),
+		Start: start, End: now()})
+}
+
+// Clone returns a new unscheduled typed job with the same task and
+// configuration delivering its results through its own channel, see Job.Clone
+func (t *TypedJob[T]) Clone() *TypedJob[T] {
+	c := &TypedJob[T]{task: t.task, buffer: t.buffer}
+	c.Job = t.Job.Clone()
+	c.Job.task = c.run
+	return c
+}
+
+// Defining the size of the results buffer, when it is full the oldest result
+// is dropped so that a slow consumer can not stall the job
+
+func (t *TypedJob[T]) Buffer(n int) *TypedJob[T] {
+	if n < 1 {
+		t.fail(fmt.Errorf("%d is not a valid results buffer size", n))
+		return t
+	}
+	if t.mutable() {
+		t.buffer = n
+	}
+	return t
+}
+
+// Start schedules the job as Job.Start() does, also returning the channel
+// where the results are delivered in order, which is closed once the job has
+// ended and its last execution has finished
+func (t *TypedJob[T]) Start() (<-chan Result[T], error) {
+	t.starting.Lock()
+	defer t.starting.Unlock()
+
+	// The channel of a started job is kept for its results
+	t.Job.lock.Lock()
+	if t.Job.started {
+		t.Job.lock.Unlock()
+		return nil, ErrStarted
+	}
+	results := make(chan Result[T], t.buffer)
+	t.results = results
+	t.Job.lock.Unlock()
+
+	if err := t.Job.Start(); err != nil {
+		return nil, err
+	}
+
+	go func() {
+		<-t.Job.done
+		t.Job.running.Wait()
+		close(results)
+	}()
+	return results, nil
+}
+
+// Done starts the job as Start() does.
+//
+// Deprecated: use Start, which returns the error last.
+func (t *TypedJob[T]) Done() (error, <-chan Result[T]) {
+	results, err := t.Start()
+	return err, results
+}
+
+// Delivers a result, dropping the oldest one if the buffer is full. Results
+// are sent while holding the job's mutex, so they keep their order
+func (t *TypedJob[T]) send(r Result[T]) {
+	t.Job.lock.Lock()
+	results := t.results
+	t.Job.lock.Unlock()
+	if results == nil {
+		return
+	}
+
+	for {
+		select {
+		case results <- r:
+			return
+		default:
+		}
+		select {
+		case <-results:
+		default:
+		}
+	}
+}
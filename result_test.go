@@ -0,0 +1,104 @@
+package chronos
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestTypedJobOnce(t *testing.T) {
+	useFakeClock(t, time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	j := ScheduleResult(func() (string, error) { return "done", nil })
+	j.Every(1).Hour().Once()
+	results, err := j.Start()
+	if err != nil {
+		t.Fatal(err)
+	}
+	r, ok := <-results
+	if !ok || r.Value != "done" || r.Err != nil || r.Run != 1 {
+		t.Fatalf("received %+v", r)
+	}
+	if r, ok := <-results; ok {
+		t.Fatalf("received %+v after the only execution", r)
+	}
+}
+
+func TestTypedJobNTimes(t *testing.T) {
+	c := useFakeClock(t, time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	fail := errors.New("odd run")
+	var runs counter
+	j := ScheduleResult(func() (int, error) {
+		runs.inc()
+		n := runs.count()
+		if n%2 == 1 {
+			return n, fail
+		}
+		return n, nil
+	})
+	j.Every(1).Minute().NTimes(3)
+	results, err := j.Start()
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.WaitTimers(t, 1)
+	c.Advance(2 * time.Minute)
+
+	run := 0
+	for r := range results {
+		run++
+		if r.Run != run || r.Value != run || (r.Err != nil) != (run%2 == 1) {
+			t.Fatalf("received %+v as result %d", r, run)
+		}
+	}
+	if run != 3 {
+		t.Fatalf("received %d results", run)
+	}
+}
+
+// Starting twice leaves the results of the first start untouched
+func TestTypedJobStarted(t *testing.T) {
+	c := useFakeClock(t, time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	j := ScheduleResult(func() (int, error) { return 1, nil })
+	j.Every(1).Minute().NotInmediately()
+	results, err := j.Start()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer j.Stop()
+	if again, err := j.Start(); err != ErrStarted || again != nil {
+		t.Fatalf("started again: %v", err)
+	}
+
+	c.WaitTimers(t, 1)
+	c.Advance(time.Minute)
+	select {
+	case r := <-results:
+		if r.Run != 1 {
+			t.Fatalf("received %+v", r)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("no result received")
+	}
+}
+
+// A full buffer drops the oldest results
+func TestTypedJobBuffer(t *testing.T) {
+	c := useFakeClock(t, time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	j := ScheduleResult(func() (int, error) { return 1, nil }).Buffer(2)
+	j.Every(1).Minute().NTimes(5)
+	results, err := j.Start()
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.WaitTimers(t, 1)
+	c.Advance(4 * time.Minute)
+	<-j.done
+
+	var runs []int
+	for r := range results {
+		runs = append(runs, r.Run)
+	}
+	if len(runs) != 2 || runs[0] != 4 || runs[1] != 5 {
+		t.Fatalf("received the results of runs %v", runs)
+	}
+}
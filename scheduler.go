@@ -5,6 +5,8 @@ package chronos
 
 import (
 	"errors"
+	"fmt"
+	"strings"
 	"time"
 )
 
@@ -13,16 +15,32 @@ const (
 	periodicKind = iota
 	monthlyKind  = iota
 	yearlyKind   = iota
+	cronKind     = iota
+	dynamicKind  = iota
+	onceKind     = iota
 )
 
+// Consecutive days skipped by a dynamic scheduler before considering it ended
+const dynamicSkipLimit = 400
+
 const (
 	Day  = 24 * time.Hour
 	Week = 7 * Day
 )
 
+// Maximum time waited in drift-corrected mode before checking the wall clock
+const driftCheck = time.Minute
+
 type scheduler interface {
-	// Returns wether there is another event scheduled and the remaining time
-	next() (bool, time.Duration)
+	// Returns wether there is another event scheduled and when it will happen
+	next() (bool, time.Time)
+}
+
+// Schedulers whose events are measured with the monotonic clock
+type monotonic interface {
+	// Returns the reading of the monotonic clock at which the event at t is
+	// due, or false if it follows the wall clock
+	due(t time.Time) (time.Duration, bool)
 }
 
 // Auxiliar type that holds the information needed to build the scheduler
@@ -32,17 +50,322 @@ type auxiliar struct {
 	notInmediately bool
 	start,
 	end time.Time
-	unit time.Duration
+	unit          time.Duration
+	months        int // Months that made up a unit in monthly schedulers
+	quarterMonth, // Month of the quarter (0-2) in quarterly schedulers
+	quarterDay int // Day of the month in quarterly schedulers, 0 if not used
+	selected, // Wether the kind and unit have been selected
+	aligned, // Wether the start is moved to a period boundary
+	weekStartSet, // Wether the first day of the week was defined
+	byISOWeek bool // Wether weeks are selected by their ISO week number
+	weekStart    time.Weekday              // First day of the week for aligned weeks
+	firstISOWeek int                       // First ISO week number selected by byISOWeek
+	alternatives []auxiliar                // Other cadences that also trigger the job
+	cron         *cronSpec                 // Parsed cron expression for cron schedulers
+	dailyAt      func(time.Time) time.Time // Event of each day for dynamic schedulers
+	offset       time.Duration             // Offset applied to dynamic events
+	atTimes      []int                     // Sorted minutes of the day of daily events
+	jitter       time.Duration             // Maximum random delay of the events
+	seed         int64                     // Seed of the random delays
+	seeded       bool                      // Wether the seed was already taken
+	skipPast     bool                      // Wether past single executions are skipped
+	pastSet      bool                      // Wether SkipIfPast or RunIfPast was called
+	windowed     bool                      // Wether events are kept within a daily window
+	windowFrom,  // Minute of the day when the window opens
+	windowTo int // Minute of the day when the window closes
+	closes bool // Wether the closings of the window are events too
+}
+
+// Returns a deep copy of the auxiliar values
+func (a auxiliar) clone() auxiliar {
+	a.atTimes = append([]int(nil), a.atTimes...)
+	if a.alternatives != nil {
+		alternatives := make([]auxiliar, len(a.alternatives))
+		for i := range a.alternatives {
+			alternatives[i] = a.alternatives[i].clone()
+		}
+		a.alternatives = alternatives
+	}
+	return a
+}
+
+// Returns a human readable name for the unit of a period
+func unitName(kind int, unit time.Duration, months int) string {
+	switch kind {
+	case monthlyKind:
+		switch months {
+		case 3:
+			return "quarter"
+		case 6:
+			return "semester"
+		}
+		return "month"
+	case yearlyKind:
+		return "year"
+	case cronKind:
+		return "cron expression"
+	case dynamicKind:
+		return "dynamic time of the day"
+	case onceKind:
+		return "single execution"
+	}
+
+	switch unit {
+	case time.Nanosecond:
+		return "nanosecond"
+	case time.Microsecond:
+		return "microsecond"
+	case time.Millisecond:
+		return "millisecond"
+	case time.Second:
+		return "second"
+	case time.Minute:
+		return "minute"
+	case time.Hour:
+		return "hour"
+	case Day:
+		return "day"
+	case Week:
+		return "week"
+	}
+	return unit.String()
+}
+
+// Returns a human readable description of the cadence
+func (a *auxiliar) describe() string {
+	var res string
+	switch {
+	case a.kind == cronKind && a.cron != nil:
+		res = "cron " + a.cron.expr
+	case a.kind == dynamicKind && a.offset != 0:
+		res = fmt.Sprintf("every day at a dynamic time %+v", a.offset)
+	case a.kind == dynamicKind:
+		res = "every day at a dynamic time"
+	case a.kind == onceKind:
+		res = "once at " + a.start.Round(0).String()
+	case len(a.atTimes) > 0:
+		res = "every day"
+		if a.selected && a.ammount > 1 {
+			res = fmt.Sprintf("every %d days", a.ammount)
+		}
+		for i, minutes := range a.atTimes {
+			sep := ", "
+			switch {
+			case i == 0:
+				sep = " at "
+			case i == len(a.atTimes)-1:
+				sep = " and "
+			}
+			res += fmt.Sprintf("%s%02d:%02d", sep, minutes/60, minutes%60)
+		}
+	case a.kind == monthlyKind && a.ammount*a.months == 1:
+		res = "monthly"
+	case a.kind == monthlyKind && a.ammount*a.months == 3:
+		res = "quarterly"
+	case a.kind == monthlyKind && a.ammount*a.months == 6:
+		res = "semiannually"
+	case a.kind == monthlyKind:
+		res = fmt.Sprintf("every %d months", a.ammount*a.months)
+	case a.ammount == 1:
+		res = "every " + unitName(a.kind, a.unit, a.months)
+	default:
+		res = fmt.Sprintf("every %d %ss", a.ammount,
+			unitName(a.kind, a.unit, a.months))
+	}
+	if a.quarterDay != 0 {
+		res += fmt.Sprintf(" on day %d of month %d of the quarter",
+			a.quarterDay, a.quarterMonth+1)
+	}
+	if a.windowed {
+		res += fmt.Sprintf(" between %02d:%02d and %02d:%02d",
+			a.windowFrom/60, a.windowFrom%60, a.windowTo/60, a.windowTo%60)
+	}
+
+	descriptions := []string{res}
+	for i := range a.alternatives {
+		descriptions = append(descriptions, a.alternatives[i].describe())
+	}
+	return strings.Join(descriptions, " or ")
+}
+
+// Returns the first period boundary at or after t, or now if t is zero, in
+// its location, or the first one after t with NotInmediately. Months and years
+// are aligned to the start of the year, so that Every(3).Months() starts on
+// January, April, July and October; weeks to their first day and days to
+// midnight. Shorter periods are aligned to midnight when they divide a day,
+// e.g. Every(6).Hours() to 00:00, 06:00, 12:00 and 18:00, or to their unit
+// otherwise.
+func (a *auxiliar) align(t time.Time) time.Time {
+	if t.IsZero() {
+		t = now()
+	}
+	year, month, day := t.Date()
+	loc := t.Location()
+
+	// Boundary at or before t and the one following it
+	var boundary, following time.Time
+	midnight := wallDate(year, month, day, 0, 0, 0, 0, loc)
+	period := time.Duration(a.ammount) * a.unit
+	switch {
+	case a.kind == monthlyKind && a.ammount*a.months > 0:
+		months := a.ammount * a.months
+		month = time.Month(int(month-1)/months*months + 1)
+		boundary = wallDate(year, month, 1, 0, 0, 0, 0, loc)
+		following = wallDate(year, month+time.Month(months), 1, 0, 0, 0, 0, loc)
+	case a.kind == yearlyKind && a.ammount > 0:
+		year -= year % a.ammount
+		boundary = wallDate(year, time.January, 1, 0, 0, 0, 0, loc)
+		following = wallDate(year+a.ammount, time.January, 1, 0, 0, 0, 0, loc)
+	case a.kind != periodicKind || period <= 0:
+		return t
+	case period%Week == 0:
+		day -= int(7+t.Weekday()-a.weekStart) % 7
+		boundary = wallDate(year, month, day, 0, 0, 0, 0, loc)
+		following = wallDate(year, month, day+7, 0, 0, 0, 0, loc)
+	case period%Day == 0:
+		boundary = midnight
+		following = wallDate(year, month, day+1, 0, 0, 0, 0, loc)
+	default:
+		if Day%period != 0 {
+			period = a.unit
+		}
+		boundary = midnight.Add(t.Sub(midnight) / period * period)
+		following = boundary.Add(period)
+	}
+
+	if boundary.Before(t) || (a.notInmediately && boundary.Equal(t)) {
+		return following
+	}
+	return boundary
+}
+
+// Returns the first instant at or after t with its weekday and time of the
+// day that falls on a week whose number, counting the weeks that start on the
+// first day of the week since the Unix epoch, is a multiple of the period in
+// weeks, so that multi-week periods keep their phase across restarts
+func (a *auxiliar) phase(t time.Time) time.Time {
+	if t.IsZero() {
+		t = now()
+	}
+	weeks := int(time.Duration(a.ammount) * a.unit / Week)
+	if weeks <= 1 {
+		return t
+	}
+
+	// Days since the epoch of the first day of the week of t, computed on the
+	// date so that DST transitions do not matter
+	year, month, day := t.Date()
+	first := day - int(7+t.Weekday()-a.weekStart)%7
+	days := time.Date(year, month, first, 0, 0, 0, 0, time.UTC).Unix() / 86400
+	// The epoch fell on a Thursday
+	days += int64(7+time.Thursday-a.weekStart) % 7
+	number := int(days / 7)
+
+	shift := (weeks - number%weeks) % weeks
+	return wallDate(year, month, day+7*shift, t.Hour(), t.Minute(), t.Second(),
+		t.Nanosecond(), t.Location())
+}
+
+// Builds the scheduler described by the auxiliar values
+func (a *auxiliar) build() (scheduler, error) {
+	var (
+		err      error
+		schedule scheduler
+	)
+
+	// Aligned starts already account for NotInmediately. Otherwise, periods of
+	// several weeks are phased by the first day of the week if defined
+	start, notInmediately := a.start, a.notInmediately
+	weeks := a.kind == periodicKind && a.unit > 0 &&
+		time.Duration(a.ammount)*a.unit%Week == 0
+	switch {
+	case a.aligned:
+		start, notInmediately = a.align(start), false
+	case a.weekStartSet && weeks && !a.byISOWeek:
+		start = a.phase(start)
+	}
+
+	switch {
+	case a.pastSet && a.kind != onceKind:
+		err = errors.New("SkipIfPast and RunIfPast only apply to single executions")
+	case len(a.atTimes) > 0 && a.selected &&
+		(a.kind != periodicKind || a.unit != Day || a.byISOWeek):
+		err = errors.New("times of the day require a period in days")
+	case len(a.atTimes) > 0:
+		days := 1
+		if a.selected {
+			days = a.ammount
+		}
+		schedule, err = newDaily(start, a.end, days, a.atTimes)
+	case a.byISOWeek && !weeks:
+		err = errors.New("selecting ISO weeks requires a period in weeks")
+	case a.byISOWeek:
+		schedule, err = newISOWeekly(start, a.end,
+			int(time.Duration(a.ammount)*a.unit/Week), a.firstISOWeek,
+			notInmediately)
+	case a.kind == periodicKind:
+		schedule, err = newPeriodic(start, a.end, a.ammount, a.unit,
+			notInmediately)
+	case a.kind == monthlyKind:
+		day := 0
+		if a.quarterDay != 0 {
+			start, day = quarterStart(start, a.quarterMonth, a.quarterDay), a.quarterDay
+		}
+		schedule, err = newMonthly(start, a.end, a.ammount*a.months, day,
+			notInmediately)
+	case a.kind == yearlyKind:
+		schedule, err = newYearly(start, a.end, a.ammount, notInmediately)
+	case a.kind == cronKind:
+		schedule, err = newCron(a.cron, start, a.end)
+	case a.kind == dynamicKind:
+		schedule, err = newDynamic(a.dailyAt, a.offset, start, a.end)
+	case a.kind == onceKind:
+		schedule, err = newOnce(a.start, a.end, a.skipPast)
+	}
+	if err == nil && a.offset != 0 && a.kind != dynamicKind {
+		err = errors.New("offsets require a dynamic time of the day")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if len(a.alternatives) > 0 {
+		schedules := []scheduler{schedule}
+		for i := range a.alternatives {
+			alternative, err := a.alternatives[i].build()
+			if err != nil {
+				return nil, err
+			}
+			schedules = append(schedules, alternative)
+		}
+		schedule = newMulti(schedules)
+	}
+	if a.jitter > 0 {
+		schedule = &jittered{schedule: schedule, max: a.jitter, seed: a.seed}
+	}
+	if a.windowed {
+		loc := time.Local
+		if !start.IsZero() {
+			loc = start.Location()
+		}
+		schedule = newWindowed(schedule, a.windowFrom, a.windowTo, loc, a.closes)
+	}
+	return schedule, nil
 }
 
 // Accepts periods in every time unit from ns to weeks, months and years need to
-// be considered separately as their length is not constant
+// be considered separately as their length is not constant. Periods are
+// measured with the monotonic clock, so they are not affected by the wall clock
+// jumping, while events keep the location of the start; the rest of the
+// schedulers follow the wall clock instead, and as all of them count their
+// events, none of them is returned twice.
 type periodic struct {
 	start, // Start time
 	end time.Time // End time, zero value means no end
 	started bool          // Internal flag to handle first executions
 	ammount time.Duration // Period
 	n       int           // Number of already executed events
+	origin  time.Duration // Reading of the monotonic clock at the start time
 }
 
 // Constructor
@@ -53,7 +376,11 @@ func newPeriodic(start, end time.Time, ammount int, unit time.Duration, notInmed
 	}
 	// If no start time was assigned, use current time
 	if start.IsZero() {
-		start = time.Now()
+		start = now()
+	}
+	// Check the ending time, if any, is after the starting time
+	if !end.IsZero() && !end.After(start) {
+		return nil, errors.New("end time not after start time")
 	}
 	// If notInmediately was called, the starting date should not be returned
 	// by periodic.next() call, so we add 1 to the event count to avoid it
@@ -63,7 +390,8 @@ func newPeriodic(start, end time.Time, ammount int, unit time.Duration, notInmed
 	}
 
 	return &periodic{start: start, end: end, started: notInmediately,
-			ammount: time.Duration(ammount * int(unit)), n: n}, nil
+		ammount: time.Duration(ammount * int(unit)), n: n,
+		origin: elapsed() + start.Sub(now())}, nil
 }
 
 // Auxiliar function that returns the execution time candidate
@@ -71,11 +399,37 @@ func (s *periodic) getCandidate() time.Time {
 	return s.start.Add(time.Duration(s.n * int(s.ammount)))
 }
 
+// Implements resumable.position()
+func (s *periodic) position() int {
+	return s.n
+}
+
+// Implements resumable.resume()
+func (s *periodic) resume(n int) {
+	s.n = n
+	s.started = true
+}
+
+// Implements seeker.seek()
+func (s *periodic) seek(t time.Time) {
+	if elapsed := t.Sub(s.start); elapsed > 0 {
+		if n := int((elapsed + s.ammount - 1) / s.ammount); n > s.n {
+			s.n = n
+		}
+	}
+	s.started = true
+}
+
+// Implements monotonic.due()
+func (s *periodic) due(t time.Time) (time.Duration, bool) {
+	return s.origin + t.Sub(s.start), true
+}
+
 // Implements scheduler.next()
-func (s *periodic) next() (bool, time.Duration) {
+func (s *periodic) next() (bool, time.Time) {
 	// Calculate the next iteration
 	next := s.getCandidate()
-	for next.Before(time.Now()) {
+	for s.origin+next.Sub(s.start) < elapsed() {
 		if !s.started {
 			break
 		}
@@ -88,28 +442,34 @@ func (s *periodic) next() (bool, time.Duration) {
 	}
 
 	// Check if the end date has arrived
-	return s.end.IsZero() || next.Before(s.end), next.Sub(time.Now())
+	return s.end.IsZero() || next.Before(s.end), next
 }
 
 // Monthly periods need to be considered separately as their length is not
-// constant (28-31 days)
+// constant (28-31 days). Events keep the time of the day of the start in its
+// location, see wallDate for the ones affected by DST transitions
 type monthly struct {
 	start, // Start time
 	end time.Time // End time, zero value means no end
 	started  bool // Internal flag to handle first executions
 	ammount, // Ammount of months that made up a period
+	day, // Day of the month, clamped to the length of shorter months
 	n int // Number of already executed events
 }
 
-// Constructor
-func newMonthly(start, end time.Time, ammount int, notInmediately bool) (*monthly, error) {
+// Constructor, a 0 day means the day of the starting time
+func newMonthly(start, end time.Time, ammount, day int, notInmediately bool) (*monthly, error) {
 	// Check the input is valid
 	if ammount == 0 {
 		return nil, errors.New("0 months is not a valid period")
 	}
 	// If no start time was assigned, use current time
 	if start.IsZero() {
-		start = time.Now()
+		start = now()
+	}
+	// Check the ending time, if any, is after the starting time
+	if !end.IsZero() && !end.After(start) {
+		return nil, errors.New("end time not after start time")
 	}
 	// If notInmediately was called, the starting date should not be returned
 	// by periodic.next() call, so we add 1 to the event count to avoid it
@@ -118,23 +478,107 @@ func newMonthly(start, end time.Time, ammount int, notInmediately bool) (*monthl
 		n = 1
 	}
 
+	if day == 0 {
+		day = start.Day()
+	}
+
 	return &monthly{start: start, end: end, started: notInmediately,
-			ammount: ammount, n: n}, nil
+		ammount: ammount, day: day, n: n}, nil
 }
 
 func (s *monthly) getCandidate() time.Time {
-	res := s.start.AddDate(0, s.n*s.ammount, 0)
-	if res.Day() != s.start.Day() {
-		res = res.AddDate(0, 0, -res.Day())
+	return monthDate(s.start, s.n*s.ammount, s.day)
+}
+
+// Returns the date months after t on the given day, clamped to the length of
+// the month, keeping the time of the day of t. The date is computed on its
+// explicit fields, as normalizing it through the location of t could change
+// the day or the time of the day around DST transitions.
+func monthDate(t time.Time, months, day int) time.Time {
+	// Months since year 0, so that the year and month are normalized
+	total := t.Year()*12 + int(t.Month()-1) + months
+	year, month := total/12, time.Month(total%12+1)
+	if total < 0 && total%12 != 0 {
+		year, month = year-1, month+12
+	}
+	if last := daysIn(year, month); day > last {
+		day = last
+	}
+	return wallDate(year, month, day, t.Hour(), t.Minute(), t.Second(),
+		t.Nanosecond(), t.Location())
+}
+
+// Returns the number of days of a month, computed in UTC as the day before
+// the next month's first one, unaffected by DST transitions
+func daysIn(year int, month time.Month) int {
+	return time.Date(year, month+1, 0, 0, 0, 0, 0, time.UTC).Day()
+}
+
+// Like time.Date, but resolving the wall clock times affected by DST
+// transitions consistently across locations: times skipped by a transition are
+// moved forward by its length and repeated times resolve to their first
+// occurrence
+func wallDate(year int, month time.Month, day, hour, min, sec, nsec int, loc *time.Location) time.Time {
+	wall := time.Date(year, month, day, hour, min, sec, nsec, time.UTC)
+	// Offsets in effect before and after any transition around the time
+	_, before := wall.Add(-Day).In(loc).Zone()
+	_, after := wall.Add(Day).In(loc).Zone()
+	first := wall.Add(-time.Duration(before) * time.Second).In(loc)
+	if before == after {
+		return first
+	}
+
+	second := wall.Add(-time.Duration(after) * time.Second).In(loc)
+	shows := func(t time.Time) bool {
+		y, m, d := t.Date()
+		return y == wall.Year() && m == wall.Month() && d == wall.Day() &&
+			t.Hour() == wall.Hour() && t.Minute() == wall.Minute()
+	}
+	switch {
+	case shows(first) && shows(second):
+		if second.Before(first) {
+			return second
+		}
+		return first
+	case shows(second):
+		return second
+	}
+	// Either the time is not affected by the transition, or it was skipped by
+	// it, in which case using the previous offset moves it forward
+	return first
+}
+
+// Returns the first time not before t that falls on the given day of the given
+// month (0-2) of a quarter, keeping the time of the day of t
+func quarterStart(t time.Time, month, day int) time.Time {
+	if t.IsZero() {
+		t = now()
+	}
+	// Months from t's month to the requested month of its quarter
+	months := month - int(t.Month()-1)%3
+	res := monthDate(t, months, day)
+	if res.Before(t) {
+		res = monthDate(t, months+3, day)
 	}
 	return res
 }
 
+// Implements resumable.position()
+func (s *monthly) position() int {
+	return s.n
+}
+
+// Implements resumable.resume()
+func (s *monthly) resume(n int) {
+	s.n = n
+	s.started = true
+}
+
 // Implements scheduler.next()
-func (s *monthly) next() (bool, time.Duration) {
+func (s *monthly) next() (bool, time.Time) {
 	// Calculate the next iteration
 	next := s.getCandidate()
-	for next.Before(time.Now()) {
+	for next.Before(now()) {
 		if !s.started {
 			break
 		}
@@ -147,7 +591,7 @@ func (s *monthly) next() (bool, time.Duration) {
 	}
 
 	// Check if the end date has arrived
-	return s.end.IsZero() || next.Before(s.end), next.Sub(time.Now())
+	return s.end.IsZero() || next.Before(s.end), next
 }
 
 // Yearly periods need to be considered separately as
@@ -168,7 +612,11 @@ func newYearly(start, end time.Time, ammount int, notInmediately bool) (*yearly,
 	}
 	// If no start time was assigned, use current time
 	if start.IsZero() {
-		start = time.Now()
+		start = now()
+	}
+	// Check the ending time, if any, is after the starting time
+	if !end.IsZero() && !end.After(start) {
+		return nil, errors.New("end time not after start time")
 	}
 	// If notInmediately was called, the starting date should not be returned
 	// by periodic.next() call, so we add 1 to the event count to avoid it
@@ -178,22 +626,29 @@ func newYearly(start, end time.Time, ammount int, notInmediately bool) (*yearly,
 	}
 
 	return &yearly{start: start, end: end, started: notInmediately,
-			ammount: ammount, n: n}, nil
+		ammount: ammount, n: n}, nil
 }
 
 func (s *yearly) getCandidate() time.Time {
-	res := s.start.AddDate(s.n*s.ammount, 0, 0)
-	if res.Day() != s.start.Day() {
-		res = res.AddDate(0, 0, -res.Day())
-	}
-	return res
+	return monthDate(s.start, 12*s.n*s.ammount, s.start.Day())
+}
+
+// Implements resumable.position()
+func (s *yearly) position() int {
+	return s.n
+}
+
+// Implements resumable.resume()
+func (s *yearly) resume(n int) {
+	s.n = n
+	s.started = true
 }
 
 // implements scheduler.next()
-func (s *yearly) next() (bool, time.Duration) {
+func (s *yearly) next() (bool, time.Time) {
 	// Calculate the next iteration
 	next := s.getCandidate()
-	for next.Before(time.Now()) {
+	for next.Before(now()) {
 		if !s.started {
 			break
 		}
@@ -206,5 +661,264 @@ func (s *yearly) next() (bool, time.Duration) {
 	}
 
 	// Check if the end date has arrived
-	return s.end.IsZero() || next.Before(s.end), next.Sub(time.Now())
+	return s.end.IsZero() || next.Before(s.end), next
+}
+
+// Weekly periods selecting the weeks by their ISO week number instead of
+// counting them from the starting time, so that the selection does not depend
+// on when the job was started. Years with 53 ISO weeks make the last week and
+// the first one of the next year be both selected when they match.
+type isoWeekly struct {
+	start, // Start time, defines the weekday and time of the day of the events
+	end time.Time // End time, zero value means no end
+	week     time.Time // Monday of the week of the next candidate
+	started  bool      // Internal flag to handle first executions
+	ammount, // Ammount of weeks that made up a period
+	first, // ISO week number of one of the selected weeks
+	days int // Days from Monday to the weekday of the events
+}
+
+// Constructor
+func newISOWeekly(start, end time.Time, ammount, first int, notInmediately bool) (*isoWeekly, error) {
+	// Check the input is valid
+	if ammount <= 0 {
+		return nil, errors.New("0 weeks is not a valid period")
+	}
+	// If no start time was assigned, use current time
+	if start.IsZero() {
+		start = now()
+	}
+	// Check the ending time, if any, is after the starting time
+	if !end.IsZero() && !end.After(start) {
+		return nil, errors.New("end time not after start time")
+	}
+
+	days := (int(start.Weekday()) + 6) % 7
+	year, month, day := start.Date()
+	week := time.Date(year, month, day-days, 0, 0, 0, 0, start.Location())
+	// If notInmediately was called, the starting week should not be returned
+	// by isoWeekly.next() call, so we skip it
+	if notInmediately {
+		week = week.AddDate(0, 0, 7)
+	}
+
+	return &isoWeekly{start: start, end: end, week: week,
+		started: notInmediately, ammount: ammount, first: first,
+		days: days}, nil
+}
+
+func (s *isoWeekly) getCandidate() time.Time {
+	year, month, day := s.week.Date()
+	return wallDate(year, month, day+s.days, s.start.Hour(), s.start.Minute(),
+		s.start.Second(), s.start.Nanosecond(), s.start.Location())
+}
+
+// Returns wether the week of the next candidate is selected
+func (s *isoWeekly) selected() bool {
+	_, week := s.week.ISOWeek()
+	return ((week-s.first)%s.ammount+s.ammount)%s.ammount == 0
+}
+
+// Implements scheduler.next()
+func (s *isoWeekly) next() (bool, time.Time) {
+	// Calculate the next iteration
+	next := s.getCandidate()
+	for !s.selected() || (s.started && next.Before(now())) {
+		s.week = s.week.AddDate(0, 0, 7)
+		next = s.getCandidate()
+	}
+	s.week = s.week.AddDate(0, 0, 7)
+	if !s.started {
+		s.started = true
+	}
+
+	// Check if the end date has arrived
+	return s.end.IsZero() || next.Before(s.end), next
+}
+
+// Single event at a given time
+type once struct {
+	at    time.Time // Time of the event
+	skip, // Wether the event is skipped if it is already past
+	ok bool // Wether the event has not been returned yet
+}
+
+// Constructor
+func newOnce(at, end time.Time, skipPast bool) (*once, error) {
+	// Check the ending time, if any, is after the event
+	if !end.IsZero() && !at.Before(end) {
+		return nil, errors.New("end time before the execution time")
+	}
+	return &once{at: at, skip: skipPast, ok: true}, nil
+}
+
+// Implements scheduler.next()
+func (s *once) next() (bool, time.Time) {
+	ok := s.ok && !(s.skip && s.at.Before(now()))
+	s.ok = false
+	if !ok {
+		return false, time.Time{}
+	}
+	return true, s.at
+}
+
+// Periods of days with several events at given times of the day
+type daily struct {
+	times []int // Sorted minutes of the day of the events
+	days, // Days between consecutive days with events
+	year int
+	month time.Month
+	day,  // Date of the next day with events
+	i int // Index of the next time of that day
+	last, // Previous event, or the instant before the start time
+	end time.Time // End time, zero value means no end
+}
+
+// Constructor
+func newDaily(start, end time.Time, days int, times []int) (*daily, error) {
+	// Check the input is valid
+	if days < 1 {
+		return nil, errors.New("invalid period size")
+	}
+	// If no start time was assigned, use current time
+	if start.IsZero() {
+		start = now()
+	}
+	// Check the ending time, if any, is after the starting time
+	if !end.IsZero() && !end.After(start) {
+		return nil, errors.New("end time not after start time")
+	}
+
+	year, month, day := start.Date()
+	return &daily{times: times, days: days, year: year, month: month,
+		day: day, last: start.Add(-time.Nanosecond), end: end}, nil
+}
+
+// Implements scheduler.next()
+func (s *daily) next() (bool, time.Time) {
+	loc := s.last.Location()
+	for {
+		if s.i == len(s.times) {
+			s.i = 0
+			s.year, s.month, s.day = time.Date(s.year, s.month, s.day+s.days,
+				0, 0, 0, 0, time.UTC).Date()
+		}
+		minutes := s.times[s.i]
+		next := wallDate(s.year, s.month, s.day, minutes/60, minutes%60, 0, 0, loc)
+		s.i++
+
+		// Events before the previous one, the start or now are skipped
+		if !next.After(s.last) || next.Before(now()) {
+			continue
+		}
+		s.last = next
+
+		// Check if the end date has arrived
+		return s.end.IsZero() || next.Before(s.end), next
+	}
+}
+
+// Daily periods whose time is chosen for each day by a function, e.g. to
+// follow the sunset. The function receives the midnight of each day in the
+// location of the start and returns the event of that day, or a zero time to
+// skip it; the offset is then added to it.
+type dynamic struct {
+	at     func(time.Time) time.Time // Event of each day
+	offset time.Duration             // Offset added to the events
+	day,   // Midnight of the next day to be considered
+	last, // Previous event, or the instant before the start time
+	end time.Time // End time, zero value means no end
+}
+
+// Constructor
+func newDynamic(at func(time.Time) time.Time, offset time.Duration, start, end time.Time) (*dynamic, error) {
+	// Check the input is valid
+	if at == nil {
+		return nil, errors.New("missing dynamic time of the day")
+	}
+	// If no start time was assigned, use current time
+	if start.IsZero() {
+		start = now()
+	}
+	// Check the ending time, if any, is after the starting time
+	if !end.IsZero() && !end.After(start) {
+		return nil, errors.New("end time not after start time")
+	}
+
+	// Events moved to the previous day by a positive offset are not lost
+	year, month, day := start.Add(-offset).Date()
+	return &dynamic{at: at, offset: offset,
+		day:  time.Date(year, month, day-1, 0, 0, 0, 0, start.Location()),
+		last: start.Add(-time.Nanosecond), end: end}, nil
+}
+
+// Implements scheduler.next()
+func (s *dynamic) next() (bool, time.Time) {
+	for skipped := 0; skipped < dynamicSkipLimit; skipped++ {
+		next := s.at(s.day)
+		year, month, day := s.day.Date()
+		s.day = time.Date(year, month, day+1, 0, 0, 0, 0, s.day.Location())
+		if next.IsZero() {
+			continue
+		}
+
+		// Events before the previous one, the start or now are skipped
+		next = next.Add(s.offset)
+		if !next.After(s.last) || next.Before(now()) {
+			continue
+		}
+		s.last = next
+
+		// Check if the end date has arrived
+		return s.end.IsZero() || next.Before(s.end), next
+	}
+	return false, time.Time{}
+}
+
+// Union of several schedulers, an event happens whenever any of them has one
+type multi struct {
+	schedules []scheduler // Composing schedulers
+	pending   []pending   // Upcoming event of each scheduler
+}
+
+// Upcoming event of a scheduler that has not happened yet
+type pending struct {
+	fetched, // Wether the scheduler has already been asked for it
+	ok bool // Wether there is such an event
+	at time.Time // Time of the event
+}
+
+// Constructor
+func newMulti(schedules []scheduler) *multi {
+	return &multi{schedules: schedules, pending: make([]pending, len(schedules))}
+}
+
+// Implements scheduler.next()
+func (s *multi) next() (bool, time.Time) {
+	var (
+		ok   bool
+		next time.Time
+	)
+
+	// Find the soonest upcoming event among all the schedulers
+	for i, schedule := range s.schedules {
+		p := &s.pending[i]
+		if !p.fetched {
+			p.ok, p.at = schedule.next()
+			p.fetched = true
+		}
+		if p.ok && (!ok || p.at.Before(next)) {
+			ok, next = true, p.at
+		}
+	}
+
+	// Only the schedulers due at that instant advance
+	for i := range s.pending {
+		p := &s.pending[i]
+		if p.ok && p.at.Equal(next) {
+			p.fetched = false
+		}
+	}
+
+	return ok, next
 }
@@ -0,0 +1,500 @@
+package chronos
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// Fails the test unless the job previews exactly the expected events
+func expectPreview(t *testing.T, j *Job, expected ...time.Time) {
+	t.Helper()
+	events, err := j.Preview(len(expected))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(events) != len(expected) {
+		t.Fatalf("previewed %v, expected %v", events, expected)
+	}
+	for i := range events {
+		if !events[i].Equal(expected[i]) {
+			t.Fatalf("previewed %v, expected %v", events, expected)
+		}
+	}
+}
+
+func TestAligned(t *testing.T) {
+	date := func(month time.Month, day, hour, min int) time.Time {
+		return time.Date(2024, month, day, hour, min, 0, 0, time.UTC)
+	}
+	useFakeClock(t, date(time.January, 10, 0, 24))
+	tests := []struct {
+		name     string
+		job      *Job
+		expected []time.Time
+	}{
+		{"on the hour", Schedule(func() {}).OnTheHour(),
+			[]time.Time{date(1, 10, 1, 0), date(1, 10, 2, 0), date(1, 10, 3, 0)}},
+		{"every 6 hours", Schedule(func() {}).Every(6).Hours().Aligned(),
+			[]time.Time{date(1, 10, 6, 0), date(1, 10, 12, 0), date(1, 10, 18, 0)}},
+		{"every 7 minutes", Schedule(func() {}).Every(7).Minutes().Aligned(),
+			[]time.Time{date(1, 10, 0, 24), date(1, 10, 0, 31)}},
+		{"at midnight", Schedule(func() {}).AtMidnight(),
+			[]time.Time{date(1, 11, 0, 0), date(1, 12, 0, 0)}},
+		{"weeks on Sunday", Schedule(func() {}).Every(1).Week().Aligned(time.Sunday),
+			[]time.Time{date(1, 14, 0, 0), date(1, 21, 0, 0)}},
+		{"quarters", Schedule(func() {}).Every(3).Months().Aligned(),
+			[]time.Time{date(4, 1, 0, 0), date(7, 1, 0, 0)}},
+		{"on a boundary", Schedule(func() {}).OnTheHour().At(date(1, 10, 1, 0)),
+			[]time.Time{date(1, 10, 1, 0), date(1, 10, 2, 0)}},
+		{"not inmediately", Schedule(func() {}).OnTheHour().At(date(1, 10, 1, 0)).
+			NotInmediately(),
+			[]time.Time{date(1, 10, 2, 0), date(1, 10, 3, 0)}},
+		{"not inmediately off the grid", Schedule(func() {}).OnTheHour().NotInmediately(),
+			[]time.Time{date(1, 10, 1, 0), date(1, 10, 2, 0)}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			expectPreview(t, test.job, test.expected...)
+		})
+	}
+}
+
+// Boundaries are those of the location of the start
+func TestAlignedLocation(t *testing.T) {
+	madrid, err := time.LoadLocation("Europe/Madrid")
+	if err != nil {
+		t.Skip(err)
+	}
+	useFakeClock(t, time.Date(2024, 1, 10, 0, 0, 0, 0, time.UTC))
+	j := Schedule(func() {}).AtMidnight().At(time.Date(2024, 3, 30, 12, 0, 0, 0, madrid))
+	expectPreview(t, j, time.Date(2024, 3, 31, 0, 0, 0, 0, madrid))
+}
+
+func TestWeeksStartingOn(t *testing.T) {
+	madrid, err := time.LoadLocation("Europe/Madrid")
+	if err != nil {
+		t.Skip(err)
+	}
+	useFakeClock(t, time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	date := func(day int) time.Time {
+		return time.Date(2024, time.January, day, 10, 30, 0, 0, madrid)
+	}
+
+	// Weekly periods keep the weekday and time of the day of the start
+	j := Schedule(func() {}).Every(1).Week().At(date(10)).WeeksStartingOn(time.Monday)
+	expectPreview(t, j, date(10), date(17), date(24))
+
+	// The phase of longer periods does not depend on the start: the week of
+	// Monday 8th is the 2819th since the epoch, so the odd one is skipped
+	for _, start := range []time.Time{date(10), date(17)} {
+		j = Schedule(func() {}).Every(2).Weeks().At(start).WeeksStartingOn(time.Monday)
+		expectPreview(t, j, date(17), date(31))
+	}
+	// Weeks starting on Thursday number from the epoch itself
+	j = Schedule(func() {}).Every(2).Weeks().At(date(10)).WeeksStartingOn(time.Thursday)
+	expectPreview(t, j, date(10), date(24))
+}
+
+// 2020 and 2026 have 53 ISO weeks, so their last week and the first one of the
+// next year are both odd
+func TestByISOWeek(t *testing.T) {
+	useFakeClock(t, time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC))
+	date := func(year int, month time.Month, day int) time.Time {
+		return time.Date(year, month, day, 9, 30, 0, 0, time.UTC)
+	}
+	tests := []struct {
+		name     string
+		job      *Job
+		expected []time.Time
+	}{
+		{"odd 2020", Schedule(func() {}).Every(2).Weeks().ByISOWeek().
+			At(date(2020, time.December, 14)),
+			[]time.Time{date(2020, time.December, 14), date(2020, time.December, 28),
+				date(2021, time.January, 4), date(2021, time.January, 18)}},
+		{"even 2020", Schedule(func() {}).Every(2).Weeks().ByISOWeek(2).
+			At(date(2020, time.December, 14)),
+			[]time.Time{date(2020, time.December, 21), date(2021, time.January, 11),
+				date(2021, time.January, 25)}},
+		{"odd 2026", Schedule(func() {}).Every(2).Weeks().ByISOWeek().
+			At(date(2026, time.December, 16)),
+			[]time.Time{date(2026, time.December, 16), date(2026, time.December, 30),
+				date(2027, time.January, 6), date(2027, time.January, 20)}},
+		{"even 2026", Schedule(func() {}).Every(2).Weeks().ByISOWeek(2).
+			At(date(2026, time.December, 16)),
+			[]time.Time{date(2026, time.December, 23), date(2027, time.January, 13),
+				date(2027, time.January, 27)}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			expectPreview(t, test.job, test.expected...)
+		})
+	}
+
+	if _, err := Schedule(func() {}).Every(1).Day().ByISOWeek().Preview(1); err == nil {
+		t.Fatal("selected ISO weeks of a period in days")
+	}
+}
+
+// Days past the end of a month are clamped to its last day
+func TestEveryQuarterOn(t *testing.T) {
+	useFakeClock(t, time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC))
+	date := func(year int, month time.Month, day int) time.Time {
+		return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+	}
+	start := date(2023, time.January, 1)
+	tests := []struct {
+		name     string
+		job      *Job
+		expected []time.Time
+	}{
+		{"last month", Schedule(func() {}).EveryQuarterOn(2, 31).At(start),
+			[]time.Time{date(2023, time.March, 31), date(2023, time.June, 30),
+				date(2023, time.September, 30), date(2023, time.December, 31)}},
+		{"first month", Schedule(func() {}).EveryQuarterOn(0, 31).At(start),
+			[]time.Time{date(2023, time.January, 31), date(2023, time.April, 30),
+				date(2023, time.July, 31), date(2023, time.October, 31)}},
+		{"leap years", Schedule(func() {}).EveryQuarterOn(1, 29).At(start),
+			[]time.Time{date(2023, time.February, 28), date(2023, time.May, 29),
+				date(2023, time.August, 29), date(2023, time.November, 29),
+				date(2024, time.February, 29)}},
+		{"every 2 quarters", Schedule(func() {}).Every(2).EveryQuarterOn(1, 15).At(start),
+			[]time.Time{date(2023, time.February, 15), date(2023, time.August, 15),
+				date(2024, time.February, 15)}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			expectPreview(t, test.job, test.expected...)
+		})
+	}
+}
+
+// Ranges ending at or before their start are rejected
+func TestInvertedRange(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	useFakeClock(t, start)
+	cadences := map[string]func() *Job{
+		"periodic": func() *Job { return Schedule(func() {}).Every(1).Hour() },
+		"monthly":  func() *Job { return Schedule(func() {}).Every(1).Month() },
+		"yearly":   func() *Job { return Schedule(func() {}).Every(1).Year() },
+		"cron":     func() *Job { return Schedule(func() {}).Cron("0 * * * *") },
+	}
+	for name, cadence := range cadences {
+		t.Run(name, func(t *testing.T) {
+			for _, end := range []time.Time{start.Add(-time.Hour), start} {
+				if err := cadence().At(start).Until(end).Start(); err == nil {
+					t.Fatalf("started a job from %v until %v", start, end)
+				}
+			}
+			j := cadence().At(start).Until(start.Add(time.Nanosecond))
+			if _, err := j.Preview(1); err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+}
+
+// Monthly and yearly events at 02:30 keep their wall clock time, except when
+// it is skipped by a spring-forward transition, which moves them an hour later
+func TestSpringForward(t *testing.T) {
+	madrid, err := time.LoadLocation("Europe/Madrid")
+	if err != nil {
+		t.Skip(err)
+	}
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skip(err)
+	}
+	date := func(year int, month time.Month, day, hour int, loc *time.Location) time.Time {
+		return time.Date(year, month, day, hour, 30, 0, 0, loc)
+	}
+	c := useFakeClock(t, time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC))
+	tests := []struct {
+		name     string
+		job      *Job
+		expected []time.Time
+	}{
+		{"monthly Madrid", Schedule(func() {}).Every(1).Month().
+			At(date(2024, time.January, 31, 2, madrid)),
+			[]time.Time{date(2024, time.January, 31, 2, madrid),
+				date(2024, time.February, 29, 2, madrid),
+				date(2024, time.March, 31, 3, madrid),
+				date(2024, time.April, 30, 2, madrid)}},
+		{"monthly New York", Schedule(func() {}).Every(1).Month().
+			At(date(2024, time.February, 10, 2, newYork)),
+			[]time.Time{date(2024, time.February, 10, 2, newYork),
+				date(2024, time.March, 10, 3, newYork),
+				date(2024, time.April, 10, 2, newYork)}},
+		{"yearly Madrid", Schedule(func() {}).Every(1).Year().
+			At(date(2023, time.March, 31, 2, madrid)),
+			[]time.Time{date(2023, time.March, 31, 2, madrid),
+				date(2024, time.March, 31, 3, madrid),
+				date(2025, time.March, 31, 2, madrid)}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			expectPreview(t, test.job, test.expected...)
+		})
+	}
+
+	// The timer waits the absolute time until the event, an hour less than
+	// the wall clock difference
+	c.Jump(time.Date(2024, time.March, 30, 12, 0, 0, 0, madrid).Sub(c.now()))
+	var runs counter
+	j := Schedule(runs.inc).Every(1).Month().NotInmediately().
+		At(date(2024, time.January, 31, 2, madrid))
+	if err := j.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer j.Stop()
+	c.WaitTimers(t, 1)
+	if next := j.NextRun(); !next.Equal(date(2024, time.March, 31, 3, madrid)) {
+		t.Fatalf("next run at %v", next)
+	}
+	c.Advance(14*time.Hour + 29*time.Minute)
+	time.Sleep(10 * time.Millisecond)
+	if runs.count() != 0 {
+		t.Fatalf("ran %d times before the event", runs.count())
+	}
+	c.Advance(time.Minute)
+	eventually(t, func() bool { return runs.count() == 1 })
+}
+
+func TestAtTimes(t *testing.T) {
+	date := func(day, hour int) time.Time {
+		return time.Date(2024, 1, day, hour, 0, 0, 0, time.UTC)
+	}
+	c := useFakeClock(t, date(1, 13))
+	tests := []struct {
+		name     string
+		job      *Job
+		expected []time.Time
+	}{
+		{"unsorted", Schedule(func() {}).Every(1).Day().AtTimes("18:00", "08:00", "12:00"),
+			[]time.Time{date(1, 18), date(2, 8), date(2, 12), date(2, 18)}},
+		{"duplicated", Schedule(func() {}).Every(1).Day().AtTimes("12:00", "08:00", "12:00"),
+			[]time.Time{date(2, 8), date(2, 12), date(3, 8)}},
+		{"every 2 days", Schedule(func() {}).Every(2).Days().AtTimes("18:00", "08:00"),
+			[]time.Time{date(1, 18), date(3, 8), date(3, 18), date(5, 8)}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			expectPreview(t, test.job, test.expected...)
+		})
+	}
+
+	// Past the last time of the day it wraps to the first one of the next day
+	var runs counter
+	j := Schedule(runs.inc).Every(1).Day().AtTimes("14:00", "08:00")
+	if err := j.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer j.Stop()
+	c.WaitTimers(t, 1)
+	c.Advance(2 * time.Hour)
+	eventually(t, func() bool { return runs.count() == 1 })
+	c.WaitTimers(t, 1)
+	if next := j.NextRun(); !next.Equal(date(2, 8)) {
+		t.Fatalf("next run at %v", next)
+	}
+	c.Advance(17 * time.Hour)
+	eventually(t, func() bool { return runs.count() == 2 })
+
+	err := Schedule(func() {}).Every(1).Day().AtTimes("08:00", "8h").Start()
+	if err == nil || !strings.Contains(err.Error(), `"8h"`) {
+		t.Fatalf("started with an invalid time: %v", err)
+	}
+}
+
+// Monthly and yearly events fall on the day of the start, or the last one of
+// shorter months, keeping its time of the day in its location
+func TestCalendarClamp(t *testing.T) {
+	madrid, err := time.LoadLocation("Europe/Madrid")
+	if err != nil {
+		t.Skip(err)
+	}
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skip(err)
+	}
+	date := func(year int, month time.Month, day int, loc *time.Location) time.Time {
+		return time.Date(year, month, day, 23, 30, 0, 0, loc)
+	}
+	useFakeClock(t, time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	tests := []struct {
+		name     string
+		job      *Job
+		expected []time.Time
+	}{
+		{"Jan 31 monthly", Schedule(func() {}).Every(1).Month().
+			At(date(2024, time.January, 31, time.UTC)),
+			[]time.Time{date(2024, time.January, 31, time.UTC),
+				date(2024, time.February, 29, time.UTC),
+				date(2024, time.March, 31, time.UTC),
+				date(2024, time.April, 30, time.UTC)}},
+		{"Jan 31 every 5 months", Schedule(func() {}).Every(5).Months().
+			At(date(2024, time.January, 31, time.UTC)),
+			[]time.Time{date(2024, time.January, 31, time.UTC),
+				date(2024, time.June, 30, time.UTC),
+				date(2024, time.November, 30, time.UTC),
+				date(2025, time.April, 30, time.UTC),
+				date(2025, time.September, 30, time.UTC)}},
+		{"Feb 29 monthly", Schedule(func() {}).Every(1).Month().
+			At(date(2024, time.February, 29, time.UTC)),
+			[]time.Time{date(2024, time.February, 29, time.UTC),
+				date(2024, time.March, 29, time.UTC),
+				date(2024, time.April, 29, time.UTC)}},
+		{"Feb 29 yearly", Schedule(func() {}).Every(1).Year().
+			At(date(2024, time.February, 29, time.UTC)),
+			[]time.Time{date(2024, time.February, 29, time.UTC),
+				date(2025, time.February, 28, time.UTC),
+				date(2026, time.February, 28, time.UTC),
+				date(2027, time.February, 28, time.UTC),
+				date(2028, time.February, 29, time.UTC)}},
+		{"Madrid spring forward", Schedule(func() {}).Every(1).Month().
+			At(date(2024, time.January, 31, madrid)),
+			[]time.Time{date(2024, time.January, 31, madrid),
+				date(2024, time.February, 29, madrid),
+				date(2024, time.March, 31, madrid),
+				date(2024, time.April, 30, madrid)}},
+		{"New York spring forward", Schedule(func() {}).Every(1).Month().
+			At(date(2024, time.February, 10, newYork)),
+			[]time.Time{date(2024, time.February, 10, newYork),
+				date(2024, time.March, 10, newYork),
+				date(2024, time.April, 10, newYork)}},
+		{"New York every 5 months", Schedule(func() {}).Every(5).Months().
+			At(date(2024, time.January, 31, newYork)),
+			[]time.Time{date(2024, time.January, 31, newYork),
+				date(2024, time.June, 30, newYork),
+				date(2024, time.November, 30, newYork)}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			expectPreview(t, test.job, test.expected...)
+		})
+	}
+}
+
+// Quarterly jobs starting on the 15th fire on the 15th every 3 months
+func TestQuarterly(t *testing.T) {
+	date := func(year int, month time.Month) time.Time {
+		return time.Date(year, month, 15, 10, 0, 0, 0, time.UTC)
+	}
+	start := date(2024, time.January)
+	c := useFakeClock(t, start)
+	quarters := []time.Time{start, date(2024, time.April), date(2024, time.July),
+		date(2024, time.October), date(2025, time.January)}
+	expectPreview(t, Schedule(func() {}).Quarterly(), quarters...)
+	expectPreview(t, Schedule(func() {}).Every(3).Months(), quarters...)
+	expectPreview(t, Schedule(func() {}).Biannually(),
+		start, date(2024, time.July), date(2025, time.January))
+
+	runs := make(chan time.Time, 10)
+	j := Schedule(func() { runs <- now() }).Quarterly().NTimes(len(quarters))
+	if err := j.Start(); err != nil {
+		t.Fatal(err)
+	}
+	for i := 1; i < len(quarters); i++ {
+		c.WaitTimers(t, 1)
+		if next := j.NextRun(); !next.Equal(quarters[i]) {
+			t.Fatalf("next run at %v", next)
+		}
+		c.Advance(j.NextRun().Sub(c.now()))
+	}
+	<-j.done
+	j.running.Wait()
+	close(runs)
+	i := 0
+	for run := range runs {
+		if !run.Equal(quarters[i]) {
+			t.Fatalf("run %d at %v", i+1, run)
+		}
+		i++
+	}
+	if i != len(quarters) {
+		t.Fatalf("ran %d times", i)
+	}
+}
+
+// Monthly and yearly jobs run on their dates as the clock goes by
+func TestCalendarRun(t *testing.T) {
+	date := func(year int, month time.Month, day int) time.Time {
+		return time.Date(year, month, day, 9, 0, 0, 0, time.UTC)
+	}
+	start := date(2024, time.January, 31)
+	tests := []struct {
+		name     string
+		job      func(f func()) *Job
+		span     time.Duration
+		expected []time.Time
+	}{
+		{"monthly", func(f func()) *Job {
+			return Schedule(f).Every(1).Month().NTimes(4)
+		}, 100 * Day, []time.Time{start, date(2024, time.February, 29),
+			date(2024, time.March, 31), date(2024, time.April, 30)}},
+		{"monthly not inmediately", func(f func()) *Job {
+			return Schedule(f).Every(2).Months().NotInmediately().NTimes(2)
+		}, 200 * Day, []time.Time{date(2024, time.March, 31), date(2024, time.May, 31)}},
+		{"yearly", func(f func()) *Job {
+			return Schedule(f).Every(1).Year().NTimes(3)
+		}, 800 * Day, []time.Time{start, date(2025, time.January, 31),
+			date(2026, time.January, 31)}},
+		{"yearly until", func(f func()) *Job {
+			return Schedule(f).Every(2).Years().Until(date(2028, time.January, 1))
+		}, 2000 * Day, []time.Time{start, date(2026, time.January, 31)}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			c := useFakeClock(t, start)
+			runs := make(chan time.Time, 10)
+			j := test.job(func() { runs <- now() })
+			if err := j.Start(); err != nil {
+				t.Fatal(err)
+			}
+			c.WaitJob(t, j)
+			c.Advance(test.span)
+			<-j.done
+			j.running.Wait()
+			close(runs)
+			i := 0
+			for run := range runs {
+				if i >= len(test.expected) || !run.Equal(test.expected[i]) {
+					t.Fatalf("run %d at %v", i+1, run)
+				}
+				i++
+			}
+			if i != len(test.expected) {
+				t.Fatalf("ran %d times", i)
+			}
+		})
+	}
+}
+
+// Days whose time is zero are skipped and the rest are moved by the offset
+func TestEveryDayAt(t *testing.T) {
+	date := func(day, hour, min int) time.Time {
+		return time.Date(2024, 1, day, hour, min, 0, 0, time.UTC)
+	}
+	c := useFakeClock(t, date(1, 0, 0))
+	at := func(day time.Time) time.Time {
+		if day.Day()%2 == 0 {
+			return time.Time{}
+		}
+		return day.Add(18 * time.Hour)
+	}
+	var runs counter
+	j := Schedule(runs.inc).EveryDayAt(at).Offset(-30 * time.Minute)
+	expectPreview(t, j, date(1, 17, 30), date(3, 17, 30), date(5, 17, 30))
+
+	if err := j.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer j.Stop()
+	c.WaitTimers(t, 1)
+	c.Advance(Day)
+	eventually(t, func() bool { return runs.count() == 1 })
+	c.WaitTimers(t, 1)
+	if next := j.NextRun(); !next.Equal(date(3, 17, 30)) {
+		t.Fatalf("next run at %v", next)
+	}
+}
@@ -0,0 +1,162 @@
+package chronos
+
+import (
+	"context"
+	"math/rand"
+	"time"
+)
+
+// The exported API is kept stable through v1. The assertions below only
+// compile while every signature stays the same, so breaking any of them
+// breaks the build of the package itself. The deprecated Done() methods are
+// left out, as they return the error first and may be removed in v2.
+
+// Constants, errors and package level functions
+var (
+	_ time.Duration = Day
+	_ time.Duration = Week
+	_ Anchor        = FromStart
+	_ Anchor        = FromNow
+	_ error         = ErrStarted
+	_ error         = ErrEnded
+
+	_ func(func()) *Job                           = Schedule
+	_ func(func(context.Context)) *Job            = ScheduleContext
+	_ func(int, func(int)) *Job                   = ScheduleArg[int]
+	_ func(func() (int, error)) *TypedJob[int]    = ScheduleResult[int]
+	_ func(time.Time, func()) *Job                = ScheduleAt
+	_ func(time.Duration, func()) *Job            = ScheduleAfter
+	_ func() *Job                                 = ScheduleTicks
+	_ func(string, func()) (*Job, error)          = ScheduleISO8601
+	_ func(func(), ...int) *Job                   = Every
+	_ func()                                      = StopAll
+	_ func(context.Context) error                 = ShutdownAll
+	_ func(rand.Source)                           = SetRandSource
+	_ func() *Manager                             = Default
+	_ func(...ManagerOption) *Manager             = NewManager
+	_ func(*Wheel) ManagerOption                  = WithWheel
+	_ func(int) *Wheel                            = NewWheel
+	_ func() *MemoryStore                         = NewMemoryStore
+	_ func(string) *FileStore                     = NewFileStore
+	_ interface{ Printf(string, ...interface{}) } = Logger(nil)
+	_ StateStore                                  = (*MemoryStore)(nil)
+	_ StateStore                                  = (*FileStore)(nil)
+	_ struct {
+		Executions int
+		LastRun    time.Time
+		Next       int
+		Start      time.Time
+	} = State{}
+	_ struct {
+		Value      int
+		Err        error
+		Run        int
+		Start, End time.Time
+	} = Result[int]{}
+)
+
+// Methods of Job
+var _ interface {
+	After(parent *Job) *Job
+	Aligned(weekStart ...time.Weekday) *Job
+	At(t time.Time) *Job
+	AtClock(t time.Time) *Job
+	AtClockString(clock string) *Job
+	AtMidnight() *Job
+	AtTimes(times ...string) *Job
+	BeforeRun(f func() bool) *Job
+	Between(from, to string) *Job
+	Biannually() *Job
+	ByISOWeek(first ...int) *Job
+	Clone() *Job
+	Cron(expr string) *Job
+	Day() *Job
+	Days() *Job
+	DroppedTicks() int
+	Err() error
+	Every(times ...int) *Job
+	EveryDayAt(at func(date time.Time) time.Time) *Job
+	EveryQuarterOn(month, day int) *Job
+	Executions() int
+	Hour() *Job
+	Hours() *Job
+	In(d time.Duration) *Job
+	Jitter(max time.Duration) *Job
+	MaxBurst(n int) *Job
+	Microsecond() *Job
+	Microseconds() *Job
+	Millisecond() *Job
+	Milliseconds() *Job
+	MinGap(d time.Duration) *Job
+	Minute() *Job
+	Minutes() *Job
+	Month() *Job
+	Months() *Job
+	NTimes(n int) *Job
+	Named(name string) *Job
+	Nanosecond() *Job
+	Nanoseconds() *Job
+	NextRun() time.Time
+	NoDrift() *Job
+	NotInmediately() *Job
+	Offset(d time.Duration) *Job
+	OnComplete(f func()) *Job
+	OnTheHour() *Job
+	OnWindowClose(f func()) *Job
+	Once() *Job
+	Or(other *Job) *Job
+	Preview(n int) ([]time.Time, error)
+	Quarter() *Job
+	Quarterly() *Job
+	Quarters() *Job
+	Remaining() int
+	Reschedule(anchor Anchor, apply func(b *Job)) error
+	Reset(amount int, unit time.Duration) error
+	Restarts() int
+	Run() error
+	RunIfPast() *Job
+	RunNow()
+	RunOnFailure() *Job
+	Second() *Job
+	Seconds() *Job
+	Semester() *Job
+	Semesters() *Job
+	Shutdown(ctx context.Context) error
+	SkipIfPast() *Job
+	Skipped() int
+	Start() error
+	Stop()
+	String() string
+	Then(f func()) *Job
+	TickBuffer(n int) *Job
+	Ticks() (<-chan time.Time, error)
+	Twice() *Job
+	Until(t time.Time) *Job
+	UsingWheel(w *Wheel) *Job
+	Week() *Job
+	Weeks() *Job
+	WeeksStartingOn(d time.Weekday) *Job
+	WithLogger(l Logger) *Job
+	WithRand(r *rand.Rand) *Job
+	WithStateStore(s StateStore) *Job
+	WithWatchdog(interval time.Duration) *Job
+	Year() *Job
+	Years() *Job
+} = (*Job)(nil)
+
+// Methods of TypedJob
+var _ interface {
+	Buffer(n int) *TypedJob[int]
+	Clone() *TypedJob[int]
+	Start() (<-chan Result[int], error)
+} = (*TypedJob[int])(nil)
+
+// Methods of Manager
+var _ interface {
+	Add(j *Job) *Job
+	After(parent *Job, f func()) *Job
+	Schedule(f func()) *Job
+	ScheduleContext(f func(context.Context)) *Job
+	ShutdownAll(ctx context.Context) error
+	StopAll()
+} = (*Manager)(nil)
@@ -0,0 +1,195 @@
+package chronos
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// State of a job that is kept across restarts
+type State struct {
+	Executions int       // Times that the task has been executed
+	LastRun    time.Time // When the last execution started
+	Next       int       // Index of the next event of the cadence
+	Start      time.Time // Anchor of the cadence that Next counts from
+}
+
+// StateStore persists the state of jobs by their name, see WithStateStore.
+// Loading the state of an unknown job returns the zero State.
+type StateStore interface {
+	Load(jobID string) (State, error)
+	Save(jobID string, s State) error
+}
+
+// Schedulers whose position can be saved and restored
+type resumable interface {
+	// Returns the index of the next event
+	position() int
+	// Continues from the given index, skipping past events
+	resume(n int)
+}
+
+// Defining the name of the job, which identifies it in its state store
+
+func (j *Job) Named(name string) *Job {
+	if !j.mutable() {
+		return j
+	}
+	j.name = name
+	return j
+}
+
+// Defining where the state of the job is kept across restarts. The state is
+// loaded by Start(), so that the count of executions and, for periodic, monthly
+// and yearly cadences, the position in the cadence continue where they were
+// left, and saved after each execution. The job must be named.
+
+func (j *Job) WithStateStore(s StateStore) *Job {
+	if !j.mutable() {
+		return j
+	}
+	j.store = s
+	return j
+}
+
+// Loads the state of the job from its store, if any, called from Start()
+func (j *Job) load() (State, error) {
+	if j.store == nil {
+		return State{}, nil
+	}
+	if j.name == "" {
+		return State{}, errors.New("jobs with a state store must be named")
+	}
+	return j.store.Load(j.name)
+}
+
+// Restores the loaded state once the job has been validated. The position in
+// the cadence is only kept if it counts from the same anchor.
+func (j *Job) restore(schedule scheduler, state State) {
+	j.n = state.Executions
+	j.lastRun = state.LastRun
+	r, ok := schedule.(resumable)
+	if ok && state.Next > 0 && state.Start.Equal(j.aux.start) {
+		r.resume(state.Next)
+		j.index = state.Next
+	}
+	if j.times != -1 && j.n >= j.times {
+		select {
+		case <-j.exhausted:
+		default:
+			close(j.exhausted)
+		}
+	}
+}
+
+// Saves the state of the job after an execution
+func (j *Job) save() {
+	j.lock.Lock()
+	state := State{Executions: j.n, LastRun: j.lastRun, Next: j.index,
+		Start: j.aux.start}
+	j.lock.Unlock()
+
+	if err := j.store.Save(j.name, state); err != nil && j.logger != nil {
+		j.logger.Printf("chronos: saving state failed: %v", err)
+	}
+}
+
+// MemoryStore keeps the states in memory, e.g. to share them between jobs of
+// the same process or for testing
+type MemoryStore struct {
+	states map[string]State // States by job name
+	mutex  sync.Mutex       // Mutex to protect the states
+}
+
+// MemoryStore construction
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{states: make(map[string]State)}
+}
+
+// Implements StateStore.Load()
+func (s *MemoryStore) Load(jobID string) (State, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	return s.states[jobID], nil
+}
+
+// Implements StateStore.Save()
+func (s *MemoryStore) Save(jobID string, state State) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.states[jobID] = state
+	return nil
+}
+
+// FileStore keeps the states of all the jobs in a JSON file, which is
+// replaced atomically on each save
+type FileStore struct {
+	path  string     // Path of the file
+	mutex sync.Mutex // Mutex to serialize the accesses to the file
+}
+
+// FileStore construction, the file is created on the first save
+func NewFileStore(path string) *FileStore {
+	return &FileStore{path: path}
+}
+
+// Implements StateStore.Load()
+func (s *FileStore) Load(jobID string) (State, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	states, err := s.read()
+	return states[jobID], err
+}
+
+// Implements StateStore.Save()
+func (s *FileStore) Save(jobID string, state State) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	states, err := s.read()
+	if err != nil {
+		return err
+	}
+	states[jobID] = state
+
+	data, err := json.Marshal(states)
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(s.path), filepath.Base(s.path)+".*")
+	if err != nil {
+		return err
+	}
+	if _, err = tmp.Write(data); err == nil {
+		err = tmp.Sync()
+	}
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), s.path)
+	}
+	if err != nil {
+		os.Remove(tmp.Name())
+	}
+	return err
+}
+
+// Reads the states of all the jobs, none if the file does not exist
+func (s *FileStore) read() (map[string]State, error) {
+	states := make(map[string]State)
+	data, err := os.ReadFile(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return states, nil
+	}
+	if err != nil {
+		return states, err
+	}
+	return states, json.Unmarshal(data, &states)
+}
@@ -0,0 +1,110 @@
+package chronos
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// A job killed and created again continues its count and its cadence
+func TestStoreResume(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	c := useFakeClock(t, start)
+	store := NewFileStore(filepath.Join(t.TempDir(), "states.json"))
+	var runs counter
+	job := func() *Job {
+		return Schedule(runs.inc).Every(1).Minute().NTimes(5).
+			Named("job").WithStateStore(store)
+	}
+
+	j := job()
+	if err := j.Start(); err != nil {
+		t.Fatal(err)
+	}
+	c.WaitTimers(t, 1)
+	c.Advance(2*time.Minute + 30*time.Second)
+	eventually(t, func() bool { return runs.count() == 3 })
+	j.Stop()
+	<-j.done
+
+	// Down while the event of the 3rd minute went by
+	c.Advance(time.Minute)
+	state, err := store.Load("job")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if state.Executions != 3 || !state.Start.Equal(start) {
+		t.Fatalf("saved %+v", state)
+	}
+
+	j = job()
+	if err := j.Start(); err != nil {
+		t.Fatal(err)
+	}
+	if n := j.Executions(); n != 3 {
+		t.Fatalf("resumed with %d executions", n)
+	}
+	c.WaitTimers(t, 1)
+	if next := j.NextRun(); !next.Equal(start.Add(4 * time.Minute)) {
+		t.Fatalf("resumed at %v, off the original cadence", next)
+	}
+	c.Advance(2 * time.Minute)
+	<-j.done
+	if runs.count() != 5 || j.Executions() != 5 {
+		t.Fatalf("ran %d times, %d executions", runs.count(), j.Executions())
+	}
+
+	// Once exhausted it does not run again
+	j = job()
+	if err := j.Start(); err != nil {
+		t.Fatal(err)
+	}
+	<-j.done
+	if runs.count() != 5 {
+		t.Fatalf("ran %d times after being exhausted", runs.count())
+	}
+}
+
+// A stored position is dropped when the job starts from a different anchor
+func TestStoreAnchor(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	c := useFakeClock(t, start)
+	store := NewMemoryStore()
+	store.Save("job", State{Executions: 2, Next: 2, Start: start.Add(-time.Hour)})
+
+	j := Schedule(func() {}).Every(1).Hour().At(start.Add(30 * time.Minute)).
+		Named("job").WithStateStore(store)
+	if err := j.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer j.Stop()
+	c.WaitTimers(t, 1)
+	if next := j.NextRun(); !next.Equal(start.Add(30 * time.Minute)) {
+		t.Fatalf("next run at %v", next)
+	}
+	if n := j.Executions(); n != 2 {
+		t.Fatalf("resumed with %d executions", n)
+	}
+}
+
+// Failing validation neither restores the state nor breaks a later attempt
+func TestStoreInvalid(t *testing.T) {
+	useFakeClock(t, time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	store := NewMemoryStore()
+	store.Save("job", State{Executions: 5})
+
+	j := Schedule(func() {}).Every(1).Minute().NTimes(5).Between("25:00", "26:00").
+		Named("job").WithStateStore(store)
+	for i := 0; i < 2; i++ {
+		if err := j.Start(); err == nil {
+			t.Fatal("invalid job started")
+		}
+	}
+	if n := j.Executions(); n != 0 {
+		t.Fatalf("restored %d executions of an invalid job", n)
+	}
+
+	if err := Schedule(func() {}).Every(1).Minute().WithStateStore(store).Start(); err == nil {
+		t.Fatal("started a job with a state store and no name")
+	}
+}
@@ -0,0 +1,88 @@
+package chronos
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Job construction without a task, whose events are delivered by Ticks()
+// instead, e.g. to replace a time.Ticker with a monthly cadence
+
+func ScheduleTicks() *Job {
+	j := ScheduleContext(nil)
+	j.tickCap = 1
+	return j
+}
+
+// Defining the size of the ticks buffer, 1 by default. When it is full, new
+// ticks are dropped so that a slow receiver can not stall the job, see
+// DroppedTicks()
+
+func (j *Job) TickBuffer(n int) *Job {
+	if n < 1 {
+		return j.fail(fmt.Errorf("%d is not a valid ticks buffer size", n))
+	}
+	if j.mutable() {
+		j.tickCap = n
+	}
+	return j
+}
+
+// Ticks schedules a job created by ScheduleTicks as Start() does, returning the
+// channel that receives the time of each event instead of running a task. The
+// channel is closed once the job has ended, either stopped or with nothing
+// else to schedule.
+func (j *Job) Ticks() (<-chan time.Time, error) {
+	j.lock.Lock()
+	if !j.started && j.task != nil {
+		j.lock.Unlock()
+		return nil, errors.New("ticks can not be delivered by a job with a task")
+	}
+	if !j.started {
+		j.task = j.tick
+		j.ticks = make(chan time.Time, j.tickCap)
+	}
+	ticks := j.ticks
+	j.lock.Unlock()
+
+	if err := j.Start(); err != nil {
+		// Failed attempts can be retried once the problems are solved
+		j.lock.Lock()
+		if !j.started {
+			j.task = nil
+			j.ticks = nil
+		}
+		j.lock.Unlock()
+		return nil, err
+	}
+	return ticks, nil
+}
+
+// DroppedTicks returns the number of ticks dropped because the buffer was full
+func (j *Job) DroppedTicks() int {
+	j.lock.Lock()
+	defer j.lock.Unlock()
+
+	return j.dropped
+}
+
+// Task of the jobs delivering ticks, sends the time of the event being
+// executed without blocking
+func (j *Job) tick(context.Context) {
+	j.lock.Lock()
+	at := j.fired
+	j.lock.Unlock()
+
+	select {
+	case j.ticks <- at:
+	default:
+		j.lock.Lock()
+		j.dropped++
+		j.lock.Unlock()
+		if j.logger != nil {
+			j.logger.Printf("chronos: tick dropped, buffer full")
+		}
+	}
+}
@@ -0,0 +1,73 @@
+package chronos
+
+import (
+	"testing"
+	"time"
+)
+
+// Ticks carry the time of their events and the channel is closed once the job
+// ends
+func TestTicks(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	c := useFakeClock(t, start)
+	ticks, err := ScheduleTicks().Every(1).Minute().NTimes(3).TickBuffer(3).Ticks()
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.WaitTimers(t, 1)
+	c.Advance(5 * time.Minute)
+	i := 0
+	for tick := range ticks {
+		if !tick.Equal(start.Add(time.Duration(i) * time.Minute)) {
+			t.Fatalf("tick %d at %v", i+1, tick)
+		}
+		i++
+	}
+	if i != 3 {
+		t.Fatalf("received %d ticks", i)
+	}
+
+	j := ScheduleTicks().Every(1).Minute()
+	if ticks, err = j.Ticks(); err != nil {
+		t.Fatal(err)
+	}
+	<-ticks
+	j.Stop()
+	if _, ok := <-ticks; ok {
+		t.Fatal("received a tick after stopping")
+	}
+}
+
+// Ticks that do not fit in the buffer are dropped
+func TestTicksDropped(t *testing.T) {
+	c := useFakeClock(t, time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	j := ScheduleTicks().Every(1).Minute().TickBuffer(2)
+	ticks, err := j.Ticks()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer j.Stop()
+	c.WaitTimers(t, 1)
+	c.Advance(4 * time.Minute)
+	eventually(t, func() bool { return j.Executions() == 5 })
+	if n := j.DroppedTicks(); n != 3 {
+		t.Fatalf("dropped %d ticks", n)
+	}
+	if len(ticks) != 2 {
+		t.Fatalf("%d ticks buffered", len(ticks))
+	}
+}
+
+func TestTicksTask(t *testing.T) {
+	useFakeClock(t, time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	j := Schedule(func() {}).Every(1).Minute()
+	if _, err := j.Ticks(); err == nil {
+		t.Fatal("delivered the ticks of a job with a task")
+	}
+	if err := ScheduleTicks().Every(1).Minute().Start(); err == nil {
+		t.Fatal("started a job without task nor ticks")
+	}
+	if _, err := ScheduleTicks().TickBuffer(0).Every(1).Minute().Ticks(); err == nil {
+		t.Fatal("accepted an empty buffer")
+	}
+}
@@ -0,0 +1,120 @@
+package chronos
+
+import (
+	"fmt"
+	"time"
+)
+
+// Defining a watchdog that checks every given interval that the scheduling
+// goroutine of the job is alive. If it died due to a panic, or its next event
+// is overdue by more than the interval, it is replaced by a new one that goes
+// on from the next event, see Restarts(). The watchdog ends with the job, when
+// it is stopped or its task's context is cancelled. Only jobs with their own
+// goroutine can be supervised.
+
+func (j *Job) WithWatchdog(interval time.Duration) *Job {
+	if interval <= 0 {
+		return j.fail(fmt.Errorf("%v is not a valid watchdog interval", interval))
+	}
+	if j.mutable() {
+		j.watchdog = interval
+	}
+	return j
+}
+
+// Restarts returns the number of times that the watchdog replaced the
+// scheduling goroutine
+func (j *Job) Restarts() int {
+	j.lock.Lock()
+	defer j.lock.Unlock()
+
+	return j.restarts
+}
+
+// Supervises the scheduling loop, restarting it whenever needed, until the job
+// ends
+func (j *Job) watch() {
+	defer j.finish()
+
+	check := newTimer(j.watchdog)
+	defer check.Stop()
+	for gen := 0; ; gen++ {
+		if !j.supervise(j.spawn(gen), check) {
+			return
+		}
+
+		j.lock.Lock()
+		j.gen = gen + 1
+		j.restarts++
+		j.lock.Unlock()
+		if j.logger != nil {
+			j.logger.Printf("chronos: watchdog restarted the scheduling goroutine")
+		}
+	}
+}
+
+// Starts a generation of the scheduling loop, returning a channel that
+// receives wether it exited cleanly
+func (j *Job) spawn(gen int) <-chan bool {
+	exited := make(chan bool, 1)
+	go func() {
+		clean := false
+		defer func() {
+			if !clean {
+				r := recover()
+				if j.logger != nil {
+					j.logger.Printf("chronos: scheduling goroutine died: %v", r)
+				}
+			}
+			exited <- clean
+		}()
+		j.serve(gen)
+		clean = true
+	}()
+	return exited
+}
+
+// Waits for a generation of the scheduling loop to end, reporting wether it
+// has to be restarted
+func (j *Job) supervise(exited <-chan bool, check timer) bool {
+	dead := false
+	for {
+		select {
+		case clean := <-exited:
+			if clean {
+				return false
+			}
+			// Restarted on the next check, so that a loop that keeps dying
+			// does not spin
+			dead, exited = true, nil
+		case <-j.ctx.Done():
+			// The generation running ends before the job does
+			j.Stop()
+			if exited != nil {
+				<-exited
+			}
+			return false
+		case <-check.C():
+			check.Reset(j.watchdog)
+			if dead || j.overdue() {
+				return true
+			}
+		}
+	}
+}
+
+// Reports wether the next event is overdue by more than the watchdog interval
+func (j *Job) overdue() bool {
+	j.lock.Lock()
+	defer j.lock.Unlock()
+
+	return !j.nextRun.IsZero() && now().Sub(j.nextRun) > j.watchdog
+}
+
+// Reports wether the given generation of the scheduling loop was replaced
+func (j *Job) replaced(gen int) bool {
+	j.lock.Lock()
+	defer j.lock.Unlock()
+
+	return j.gen != gen
+}
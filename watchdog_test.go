@@ -0,0 +1,114 @@
+package chronos
+
+import (
+	"fmt"
+	"runtime"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// Logger breaking the scheduling goroutine the first time it arms a timer,
+// either panicking or blocking until released
+type breakingLogger struct {
+	recordingLogger
+	release chan struct{} // Nil to panic instead of blocking
+	broken  bool          // Wether the goroutine was already broken
+}
+
+func (l *breakingLogger) Printf(format string, v ...interface{}) {
+	l.recordingLogger.Printf(format, v...)
+	l.mutex.Lock()
+	breaks := !l.broken && strings.HasPrefix(format, "chronos: timer armed")
+	l.broken = l.broken || breaks
+	l.mutex.Unlock()
+	if !breaks {
+		return
+	}
+	if l.release == nil {
+		panic("broken logger")
+	}
+	<-l.release
+}
+
+// Reports wether the logger received a message starting with prefix
+func (l *breakingLogger) logged(prefix string) bool {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	for _, m := range l.messages {
+		if strings.HasPrefix(m, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+func TestWatchdog(t *testing.T) {
+	for _, stall := range []bool{false, true} {
+		t.Run(fmt.Sprintf("stall %v", stall), func(t *testing.T) {
+			c := useFakeClock(t, time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+			l := &breakingLogger{}
+			if stall {
+				l.release = make(chan struct{})
+			}
+			var runs counter
+			j := Schedule(runs.inc).Every(1).Minute().NotInmediately().
+				WithWatchdog(30 * time.Second).WithLogger(l)
+			if err := j.Start(); err != nil {
+				t.Fatal(err)
+			}
+			defer j.Stop()
+
+			// Only the timer of the watchdog is armed
+			c.WaitTimers(t, 1)
+			c.Advance(2 * time.Minute)
+			eventually(t, func() bool { return j.Restarts() == 1 })
+			if !l.logged("chronos: watchdog restarted the scheduling goroutine") {
+				t.Fatal("the restart was not logged")
+			}
+			if !stall && !l.logged("chronos: scheduling goroutine died: broken logger") {
+				t.Fatal("the panic was not logged")
+			}
+			if stall {
+				close(l.release)
+			}
+
+			// The new goroutine goes on with the cadence
+			c.WaitTimers(t, 2)
+			c.Advance(3 * time.Minute)
+			eventually(t, func() bool { return runs.count() >= 2 })
+			if j.Restarts() != 1 {
+				t.Fatalf("restarted %d times", j.Restarts())
+			}
+		})
+	}
+}
+
+// The watchdog ends with the job, when it is stopped or its context cancelled
+func TestWatchdogEnd(t *testing.T) {
+	useFakeClock(t, time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	before := runtime.NumGoroutine()
+	var jobs []*Job
+	for i := 0; i < 2; i++ {
+		j := Schedule(func() {}).Every(1).Minute().WithWatchdog(time.Minute)
+		if err := j.Start(); err != nil {
+			t.Fatal(err)
+		}
+		jobs = append(jobs, j)
+	}
+	jobs[0].Stop()
+	jobs[1].cancel()
+
+	var wg sync.WaitGroup
+	for _, j := range jobs {
+		wg.Add(1)
+		go func(j *Job) {
+			defer wg.Done()
+			<-j.done
+		}(j)
+	}
+	wg.Wait()
+	eventually(t, func() bool { return runtime.NumGoroutine() <= before })
+}
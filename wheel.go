@@ -0,0 +1,289 @@
+package chronos
+
+import (
+	"container/heap"
+	"runtime"
+	"sync"
+	"time"
+)
+
+// Executions that can wait for a worker per worker before the wheel blocks
+const wheelBacklog = 64
+
+// Wheel is an alternative backend that multiplexes the scheduling of many jobs
+// onto a single goroutine, which keeps them in a min-heap ordered by their next
+// event, and runs their tasks on a fixed pool of workers, instead of using a
+// goroutine and a timer per job. Jobs on a wheel are controlled through Stop,
+// RunNow and Reschedule; signals sent directly on the channels returned by
+// Done are only noticed the next time the job is due, before firing it. Stop
+// ends the wheel with all its jobs.
+type Wheel struct {
+	work    chan *Job     // Executions waiting for a worker
+	signal  chan struct{} // Signals that there are woken jobs
+	quit    chan struct{} // Closed when the wheel is stopped
+	woken   []*Job        // Jobs added or whose channels were signalled
+	stopped bool          // Wether the wheel has been stopped
+	mutex   sync.Mutex    // Mutex to protect woken and stopped
+}
+
+// Wheel construction, a non-positive number of workers means GOMAXPROCS
+func NewWheel(workers int) *Wheel {
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+
+	w := &Wheel{work: make(chan *Job, workers*wheelBacklog),
+		signal: make(chan struct{}, 1), quit: make(chan struct{})}
+	for i := 0; i < workers; i++ {
+		go w.worker()
+	}
+	go w.loop()
+	return w
+}
+
+// Defining the wheel that schedules the job instead of its own goroutine
+
+func (j *Job) UsingWheel(w *Wheel) *Job {
+	if !j.mutable() {
+		return j
+	}
+	j.wheel = w
+	return j
+}
+
+// Stop ends every job of the wheel as if each one was stopped, and its
+// goroutines once the executions already dispatched have finished. Jobs can
+// not be started on a stopped wheel.
+func (w *Wheel) Stop() {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	if !w.stopped {
+		w.stopped = true
+		close(w.quit)
+	}
+}
+
+// Reports wether the wheel has been stopped
+func (w *Wheel) isStopped() bool {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	return w.stopped
+}
+
+// Lets the scheduling goroutine know that a job was added or signalled
+func (w *Wheel) wake(j *Job) {
+	w.mutex.Lock()
+	if w.stopped {
+		w.mutex.Unlock()
+		// A job started while the wheel stopped ends right away
+		j.finish()
+		return
+	}
+	w.woken = append(w.woken, j)
+	w.mutex.Unlock()
+
+	select {
+	case w.signal <- struct{}{}:
+	default:
+	}
+}
+
+// Runs the executions dispatched by the scheduling goroutine
+func (w *Wheel) worker() {
+	for j := range w.work {
+		j.run()
+	}
+}
+
+// Scheduling loop, runs in its own goroutine for all the jobs of the wheel
+func (w *Wheel) loop() {
+	var (
+		queue   wheelQueue
+		entries = make(map[*Job]*wheelEntry)
+		timer   timer
+		timeout <-chan time.Time
+	)
+	for {
+		// Fire every due job and arm the timer for the soonest of the rest
+		timeout = nil
+		for len(queue) > 0 {
+			e := queue[0]
+			if d := e.job.delay(e.next); d > 0 {
+				if timer != nil {
+					timer.Stop()
+				}
+				timer = newTimer(d)
+				timeout = timer.C()
+				break
+			}
+			// Signals sent directly on the channels of the job
+			if w.check(&queue, entries, e.job) {
+				continue
+			}
+			if e.job.logger != nil {
+				e.job.logger.Printf("chronos: fired")
+			}
+			e.job.dispatch(e.next)
+			w.advance(&queue, entries, e)
+		}
+
+		select {
+		case <-timeout:
+		case <-w.signal:
+			w.mutex.Lock()
+			woken := w.woken
+			w.woken = nil
+			w.mutex.Unlock()
+
+			for _, j := range woken {
+				w.check(&queue, entries, j)
+			}
+		case <-w.quit:
+			w.close(entries)
+			return
+		}
+	}
+}
+
+// Ends the jobs of a stopped wheel and lets its workers exit once the
+// dispatched executions have finished
+func (w *Wheel) close(entries map[*Job]*wheelEntry) {
+	w.mutex.Lock()
+	woken := w.woken
+	w.woken = nil
+	w.mutex.Unlock()
+
+	for j := range entries {
+		if j.logger != nil {
+			j.logger.Printf("chronos: stopped with the wheel")
+		}
+		j.finish()
+	}
+	// Jobs that were added but not taken yet, finishing twice is harmless
+	for _, j := range woken {
+		j.finish()
+	}
+	close(w.work)
+}
+
+// Handles a job that was added or whose channels were signalled, reporting
+// wether its entry changed
+func (w *Wheel) check(queue *wheelQueue, entries map[*Job]*wheelEntry, j *Job) bool {
+	e, ok := entries[j]
+	if !ok {
+		// Finished jobs can still be signalled
+		select {
+		case <-j.done:
+			return false
+		default:
+			e = &wheelEntry{job: j, index: -1}
+			entries[j] = e
+			w.advance(queue, entries, e)
+			return true
+		}
+	}
+
+	select {
+	case <-j.quit:
+		if j.logger != nil {
+			j.logger.Printf("chronos: stopped")
+		}
+		w.remove(queue, entries, e)
+		return true
+	case <-j.exhausted:
+		if j.logger != nil {
+			j.logger.Printf("chronos: completed, execution count exhausted")
+		}
+		w.remove(queue, entries, e)
+		return true
+	default:
+	}
+
+	select {
+	case <-j.skip:
+		if j.logger != nil {
+			j.logger.Printf("chronos: fired on demand")
+		}
+		j.dispatch(now())
+		w.advance(queue, entries, e)
+		return true
+	case <-j.reset:
+		// The new event may have already been taken by advance()
+		if j.rearmed() {
+			if j.logger != nil {
+				j.logger.Printf("chronos: rescheduled")
+			}
+			w.advance(queue, entries, e)
+			return true
+		}
+	default:
+	}
+	return false
+}
+
+// Moves a job to its next event, removing it if it has completed
+func (w *Wheel) advance(queue *wheelQueue, entries map[*Job]*wheelEntry, e *wheelEntry) {
+	ok, next := e.job.advance()
+	if !ok {
+		w.remove(queue, entries, e)
+		return
+	}
+
+	e.next = next
+	if e.index < 0 {
+		heap.Push(queue, e)
+	} else {
+		heap.Fix(queue, e.index)
+	}
+}
+
+// Removes a job from the wheel and releases it
+func (w *Wheel) remove(queue *wheelQueue, entries map[*Job]*wheelEntry, e *wheelEntry) {
+	if e.index >= 0 {
+		heap.Remove(queue, e.index)
+	}
+	delete(entries, e.job)
+	e.job.finish()
+}
+
+// Job scheduled by a wheel
+type wheelEntry struct {
+	job   *Job      // Scheduled job
+	next  time.Time // Next event of the job
+	index int       // Position in the queue, -1 if not queued
+}
+
+// Min-heap of the scheduled jobs ordered by their next event, implements
+// heap.Interface
+type wheelQueue []*wheelEntry
+
+func (q wheelQueue) Len() int {
+	return len(q)
+}
+
+func (q wheelQueue) Less(i, j int) bool {
+	return q[i].next.Before(q[j].next)
+}
+
+func (q wheelQueue) Swap(i, j int) {
+	q[i], q[j] = q[j], q[i]
+	q[i].index = i
+	q[j].index = j
+}
+
+func (q *wheelQueue) Push(x interface{}) {
+	e := x.(*wheelEntry)
+	e.index = len(*q)
+	*q = append(*q, e)
+}
+
+func (q *wheelQueue) Pop() interface{} {
+	old := *q
+	e := old[len(old)-1]
+	old[len(old)-1] = nil
+	e.index = -1
+	*q = old[:len(old)-1]
+	return e
+}
@@ -0,0 +1,155 @@
+package chronos
+
+import (
+	"fmt"
+	"runtime"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestWheel(t *testing.T) {
+	c := useFakeClock(t, time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	w := NewWheel(4)
+	defer w.Stop()
+
+	var runs counter
+	for i := 0; i < 100; i++ {
+		j := Schedule(runs.inc).Every(1).Minute().NotInmediately().NTimes(3).
+			UsingWheel(w)
+		if err := j.Start(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	c.WaitTimers(t, 1)
+	c.Advance(3 * time.Minute)
+	eventually(t, func() bool { return runs.count() == 300 })
+}
+
+func TestWheelControl(t *testing.T) {
+	useFakeClock(t, time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	w := NewWheel(2)
+	defer w.Stop()
+
+	var runs counter
+	j := Schedule(runs.inc).Every(1).Hour().NotInmediately().UsingWheel(w)
+	if err := j.Start(); err != nil {
+		t.Fatal(err)
+	}
+	j.RunNow()
+	eventually(t, func() bool { return runs.count() == 1 })
+	j.Stop()
+	<-j.done
+}
+
+// Signals sent on the channels returned by Done are noticed before the job
+// fires again
+func TestWheelSignals(t *testing.T) {
+	c := useFakeClock(t, time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	w := NewWheel(2)
+	defer w.Stop()
+
+	var runs counter
+	j := Schedule(runs.inc).Every(1).Minute().NotInmediately().UsingWheel(w)
+	err, _, quit := j.Done()
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.WaitTimers(t, 1)
+	c.Advance(time.Minute)
+	eventually(t, func() bool { return runs.count() == 1 })
+	quit <- struct{}{}
+	c.Advance(10 * time.Minute)
+	<-j.done
+	time.Sleep(10 * time.Millisecond)
+	if runs.count() != 1 {
+		t.Fatalf("ran %d times after being stopped", runs.count())
+	}
+}
+
+// Stopping or running a job before it is started must not reach the wheel
+func TestWheelUnstarted(t *testing.T) {
+	useFakeClock(t, time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	w := NewWheel(2)
+	defer w.Stop()
+	m := NewManager(WithWheel(w))
+	var runs counter
+	j := m.Schedule(runs.inc).Every(1).Seconds()
+	j.RunNow()
+	m.StopAll()
+	// Give the wheel the chance to take the job
+	time.Sleep(10 * time.Millisecond)
+
+	// Once started, the job is scheduled and controlled as usual
+	if err := j.Start(); err != nil {
+		t.Fatal(err)
+	}
+	j.Stop()
+	<-j.done
+}
+
+func TestWheelStop(t *testing.T) {
+	useFakeClock(t, time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	before := runtime.NumGoroutine()
+	w := NewWheel(4)
+
+	jobs := make([]*Job, 10)
+	for i := range jobs {
+		jobs[i] = Schedule(func() {}).Every(1).Minute().NotInmediately().UsingWheel(w)
+		if err := jobs[i].Start(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	w.Stop()
+	w.Stop()
+	for _, j := range jobs {
+		<-j.done
+	}
+	if err := Schedule(func() {}).Every(1).Minute().UsingWheel(w).Start(); err == nil {
+		t.Fatal("started a job on a stopped wheel")
+	}
+	eventually(t, func() bool { return runtime.NumGoroutine() <= before })
+}
+
+// Schedules n jobs firing once after a short delay and waits for all of them,
+// reporting the goroutines alive while they wait
+func benchmarkJobs(b *testing.B, n int, wheel bool) {
+	b.ReportAllocs()
+	var goroutines int
+	for i := 0; i < b.N; i++ {
+		var w *Wheel
+		if wheel {
+			w = NewWheel(0)
+		}
+		var wg sync.WaitGroup
+		wg.Add(n)
+		// Late enough for every job to be waiting when the goroutines are counted
+		at := time.Now().Add(100*time.Millisecond + time.Duration(n)*10*time.Microsecond)
+		for k := 0; k < n; k++ {
+			j := ScheduleAt(at, wg.Done)
+			if w != nil {
+				j.UsingWheel(w)
+			}
+			if err := j.Start(); err != nil {
+				b.Fatal(err)
+			}
+		}
+		goroutines = runtime.NumGoroutine()
+		wg.Wait()
+		if w != nil {
+			w.Stop()
+		}
+	}
+	b.ReportMetric(float64(goroutines), "goroutines")
+}
+
+func BenchmarkJobs(b *testing.B) {
+	for _, n := range []int{10000, 100000} {
+		b.Run(fmt.Sprintf("goroutine-%dk", n/1000), func(b *testing.B) {
+			benchmarkJobs(b, n, false)
+		})
+		b.Run(fmt.Sprintf("wheel-%dk", n/1000), func(b *testing.B) {
+			benchmarkJobs(b, n, true)
+		})
+	}
+}
@@ -0,0 +1,177 @@
+package chronos
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Consecutive windows without events before considering a windowed scheduler
+// ended, as the events of its cadence may never fall within them
+const windowSkipLimit = 400
+
+// Schedulers that can skip their events before a given time without computing
+// them one by one
+type seeker interface {
+	seek(t time.Time)
+}
+
+// Defining a daily window, between two times of the day given as "15:04" in
+// the location of the starting time, out of which events are skipped without
+// counting as executions. Windows can cross midnight, e.g. from "22:00" to
+// "02:00".
+
+func (j *Job) Between(from, to string) *Job {
+	if !j.mutable() {
+		return j
+	}
+	bounds := [2]int{}
+	for i, clock := range []string{from, to} {
+		t, err := time.Parse("15:04", clock)
+		if err != nil {
+			return j.fail(fmt.Errorf("%q is not a valid time of the day", clock))
+		}
+		bounds[i] = 60*t.Hour() + t.Minute()
+	}
+	if bounds[0] == bounds[1] {
+		return j.fail(errors.New("empty window"))
+	}
+	j.aux.windowed = true
+	j.aux.windowFrom, j.aux.windowTo = bounds[0], bounds[1]
+	return j
+}
+
+// Defining a hook that runs each time the window defined by Between closes
+// after an execution, e.g. to flush what the executions gathered
+
+func (j *Job) OnWindowClose(f func()) *Job {
+	if !j.mutable() {
+		return j
+	}
+	j.onClose = f
+	j.aux.closes = f != nil
+	return j
+}
+
+// Runs the OnWindowClose hook if the event at the given time is the closing of
+// a window, reporting wether it was
+func (j *Job) closing(at time.Time) bool {
+	j.lock.Lock()
+	w, ok := j.schedule.(*windowed)
+	closing := ok && w.closing(at)
+	j.lock.Unlock()
+	if !closing {
+		return false
+	}
+
+	if j.logger != nil {
+		j.logger.Printf("chronos: window closed")
+	}
+	if j.blocking {
+		j.onClose()
+		return true
+	}
+	j.running.Add(1)
+	go func() {
+		defer j.running.Done()
+		j.onClose()
+	}()
+	return true
+}
+
+// Scheduler keeping the events of another one within a daily window, and
+// optionally adding an event when the window closes after them
+type windowed struct {
+	schedule scheduler      // Scheduler whose events are filtered
+	from, to int            // Minutes of the day when the window opens and closes
+	loc      *time.Location // Location of the times of the day
+	closes   bool           // Wether the closings are events too
+	fetched, // Wether the upcoming event of schedule was fetched
+	ok bool // Wether there is such an event
+	at, // Time of that event
+	closeAt, // Closing time of the window of the last returned event
+	closed time.Time // Last returned closing event, if it was the last one
+}
+
+// Constructor
+func newWindowed(schedule scheduler, from, to int, loc *time.Location, closes bool) *windowed {
+	return &windowed{schedule: schedule, from: from, to: to, loc: loc,
+		closes: closes}
+}
+
+// Implements monotonic.due()
+func (s *windowed) due(t time.Time) (time.Duration, bool) {
+	if m, ok := s.schedule.(monotonic); ok && !s.closing(t) {
+		return m.due(t)
+	}
+	return 0, false
+}
+
+// Implements scheduler.next()
+func (s *windowed) next() (bool, time.Time) {
+	s.closed = time.Time{}
+	var skipped time.Time // Opening of the last window whose events were skipped
+	for windows := 0; ; {
+		if !s.fetched {
+			s.ok, s.at = s.schedule.next()
+			s.fetched = true
+		}
+		// The window of the last event closes before the upcoming one
+		if s.closes && !s.closeAt.IsZero() && (!s.ok || !s.at.Before(s.closeAt)) {
+			s.closed, s.closeAt = s.closeAt, time.Time{}
+			return true, s.closed
+		}
+		if !s.ok {
+			return false, time.Time{}
+		}
+
+		opens, closes := s.window(s.at)
+		if s.at.Before(opens) {
+			if !opens.Equal(skipped) {
+				skipped = opens
+				if windows++; windows > windowSkipLimit {
+					s.ok = false
+					return false, time.Time{}
+				}
+			}
+			// Jump straight to the opening when possible
+			if seeker, ok := s.schedule.(seeker); ok {
+				seeker.seek(opens)
+			}
+			s.fetched = false
+			continue
+		}
+		s.fetched = false
+		if s.closes {
+			s.closeAt = closes
+		}
+		return true, s.at
+	}
+}
+
+// Reports wether the event at the given time was the closing of a window
+func (s *windowed) closing(at time.Time) bool {
+	return !s.closed.IsZero() && s.closed.Equal(at)
+}
+
+// Returns the opening and closing times of the window containing t, or of the
+// next one if t is out of any
+func (s *windowed) window(t time.Time) (time.Time, time.Time) {
+	t = t.In(s.loc)
+	year, month, day := t.Date()
+	at := func(day, minutes int) time.Time {
+		return wallDate(year, month, day, minutes/60, minutes%60, 0, 0, s.loc)
+	}
+
+	if s.from < s.to {
+		if !t.Before(at(day, s.to)) {
+			day++
+		}
+		return at(day, s.from), at(day, s.to)
+	}
+	// Windows crossing midnight
+	if t.Before(at(day, s.to)) {
+		return at(day-1, s.from), at(day, s.to)
+	}
+	return at(day, s.from), at(day+1, s.to)
+}
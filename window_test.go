@@ -0,0 +1,118 @@
+package chronos
+
+import (
+	"testing"
+	"time"
+)
+
+func TestBetween(t *testing.T) {
+	date := func(day, hour int) time.Time {
+		return time.Date(2024, 1, day, hour, 0, 0, 0, time.UTC)
+	}
+	useFakeClock(t, date(1, 12))
+	tests := []struct {
+		name     string
+		job      *Job
+		expected []time.Time
+	}{
+		{"within the day", Schedule(func() {}).Every(1).Hour().Between("09:00", "11:00"),
+			[]time.Time{date(2, 9), date(2, 10), date(3, 9)}},
+		{"crossing midnight", Schedule(func() {}).Every(1).Hour().Between("22:00", "02:00"),
+			[]time.Time{date(1, 22), date(1, 23), date(2, 0), date(2, 1), date(2, 22)}},
+		{"cron", Schedule(func() {}).Cron("0 */6 * * *").Between("05:00", "13:00"),
+			[]time.Time{date(2, 6), date(2, 12), date(3, 6)}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			expectPreview(t, test.job, test.expected...)
+		})
+	}
+}
+
+// Cadences never falling within the window end instead of searching forever
+func TestBetweenNever(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	useFakeClock(t, start)
+	for _, j := range []*Job{
+		Schedule(func() {}).Every(1).Hour().At(start.Add(8*time.Hour+30*time.Minute)).
+			Between("09:00", "09:15"),
+		Schedule(func() {}).Cron("0 8 * * *").Between("09:00", "10:00"),
+		Schedule(func() {}).Every(1).Month().Between("09:00", "10:00"),
+	} {
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			if events, err := j.Preview(1); err != nil || len(events) != 0 {
+				t.Errorf("previewed %v, %v", events, err)
+			}
+			if err := j.Start(); err != nil {
+				t.Error(err)
+				return
+			}
+			<-j.done
+		}()
+		select {
+		case <-done:
+		case <-time.After(5 * time.Second):
+			t.Fatal("searching for an event within the window did not end")
+		}
+	}
+}
+
+// Events out of the window are skipped without counting as executions
+func TestBetweenCount(t *testing.T) {
+	start := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	c := useFakeClock(t, start)
+	var runs counter
+	j := Schedule(runs.inc).Every(1).Hour().Between("22:00", "02:00").NTimes(5)
+	if err := j.Start(); err != nil {
+		t.Fatal(err)
+	}
+	c.WaitTimers(t, 1)
+	c.Advance(14 * time.Hour)
+	eventually(t, func() bool { return runs.count() == 4 })
+	if j.Remaining() != 1 || j.Skipped() != 0 {
+		t.Fatalf("%d remaining, %d skipped", j.Remaining(), j.Skipped())
+	}
+	c.WaitTimers(t, 1)
+	if next := j.NextRun(); !next.Equal(time.Date(2024, 1, 2, 22, 0, 0, 0, time.UTC)) {
+		t.Fatalf("next run at %v", next)
+	}
+	c.Advance(Day)
+	<-j.done
+	j.running.Wait()
+	if runs.count() != 5 {
+		t.Fatalf("ran %d times", runs.count())
+	}
+}
+
+// The hook runs when the window closes after executions, once per window
+func TestOnWindowClose(t *testing.T) {
+	start := time.Date(2024, 1, 1, 8, 0, 0, 0, time.UTC)
+	c := useFakeClock(t, start)
+	var runs counter
+	closes := make(chan time.Time, 10)
+	j := Schedule(runs.inc).Every(30).Minutes().Between("09:00", "10:00").
+		OnWindowClose(func() { closes <- now() })
+	if err := j.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer j.Stop()
+	expectPreview(t, j, start.Add(time.Hour), start.Add(90*time.Minute),
+		start.Add(25*time.Hour))
+
+	c.WaitTimers(t, 1)
+	c.Advance(2 * time.Hour)
+	eventually(t, func() bool { return len(closes) == 1 })
+	if at := <-closes; !at.Equal(start.Add(2 * time.Hour)) {
+		t.Fatalf("window closed at %v", at)
+	}
+	if runs.count() != 2 || j.Executions() != 2 {
+		t.Fatalf("ran %d times, %d executions", runs.count(), j.Executions())
+	}
+	c.Advance(Day)
+	eventually(t, func() bool { return len(closes) == 1 })
+	if runs.count() != 4 {
+		t.Fatalf("ran %d times", runs.count())
+	}
+}